@@ -0,0 +1,375 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package accumulator
+
+import (
+	"errors"
+	"math/big"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrMember         = errors.New("the element is accumulated")
+	ErrNotMember      = errors.New("the element is not accumulated")
+	ErrInvalidElement = errors.New("the element can't be accumulated")
+	ErrInvalidWitness = errors.New("invalid witness")
+	ErrInvalidUpdate  = errors.New("invalid update")
+	ErrUpdatedElement = errors.New("the updates add or remove the element of the witness")
+)
+
+// PublicKey public parameters of the accumulator, used to verify the witnesses and the updates
+type PublicKey struct {
+	G1  bls12377.G1Affine // generator of G1
+	G2  bls12377.G2Affine // generator of G2
+	SG2 bls12377.G2Affine // [s]G2, s being the secret of the manager
+}
+
+// Accumulator set of elements of fr, accumulated into Value = [Prod_{x in X} (x + s)]G1.
+// It is maintained by the manager, who knows the secret s.
+type Accumulator struct {
+	PublicKey PublicKey
+	Value     bls12377.G1Affine
+
+	secret   fr.Element
+	value    fr.Element // Prod_{x in X} (x + s)
+	elements map[fr.Element]struct{}
+}
+
+// MembershipWitness witness that Element is accumulated: W = [Prod_{x in X, x != Element} (x + s)]G1,
+// so that e(W, [Element + s]G2) == e(Value, G2)
+type MembershipWitness struct {
+	Element fr.Element
+	W       bls12377.G1Affine
+}
+
+// NonMembershipWitness witness that Element is not accumulated.
+// With f(X) = Prod_{x in X} (x + X) = q(X)*(X + Element) + D, D = f(-Element) is not zero,
+// and W = [q(s)]G1 so that e(W, [Element + s]G2)*e([D]G1, G2) == e(Value, G2)
+type NonMembershipWitness struct {
+	Element fr.Element
+	W       bls12377.G1Affine
+	D       fr.Element
+}
+
+// Update change of the accumulator value when an element is added or removed.
+// The updates are published by the manager, so that the holders of witnesses can update them.
+type Update struct {
+	Element fr.Element
+	Removed bool              // true if Element is removed, false if it is added
+	Old     bls12377.G1Affine // value of the accumulator before the update
+	New     bls12377.G1Affine // value of the accumulator after the update
+}
+
+// New returns an accumulator of elements, whose secret is secret.
+// The secret is the trapdoor of the accumulator: it must be random, and known only to the manager.
+func New(secret *big.Int, elements []fr.Element) (*Accumulator, error) {
+	var acc Accumulator
+	acc.secret.SetBigInt(secret)
+	_, _, acc.PublicKey.G1, acc.PublicKey.G2 = bls12377.Generators()
+	acc.PublicKey.SG2.ScalarMultiplication(&acc.PublicKey.G2, acc.secret.ToBigIntRegular(new(big.Int)))
+	acc.elements = make(map[fr.Element]struct{}, len(elements))
+	acc.value.SetOne()
+
+	if err := acc.checkAdd(elements); err != nil {
+		return nil, err
+	}
+	var t fr.Element
+	for i := 0; i < len(elements); i++ {
+		acc.elements[elements[i]] = struct{}{}
+		t.Add(&elements[i], &acc.secret)
+		acc.value.Mul(&acc.value, &t)
+	}
+	acc.Value.ScalarMultiplication(&acc.PublicKey.G1, acc.value.ToBigIntRegular(new(big.Int)))
+
+	return &acc, nil
+}
+
+// Len returns the number of accumulated elements
+func (acc *Accumulator) Len() int {
+	return len(acc.elements)
+}
+
+// Contains returns true if x is accumulated
+func (acc *Accumulator) Contains(x *fr.Element) bool {
+	_, ok := acc.elements[*x]
+	return ok
+}
+
+// Add accumulates the elements, and returns the corresponding updates.
+// If an element is already accumulated, nothing is added.
+func (acc *Accumulator) Add(elements ...fr.Element) ([]Update, error) {
+	if err := acc.checkAdd(elements); err != nil {
+		return nil, err
+	}
+
+	scalars := make([]fr.Element, len(elements))
+	var t fr.Element
+	for i := 0; i < len(elements); i++ {
+		acc.elements[elements[i]] = struct{}{}
+		t.Add(&elements[i], &acc.secret)
+		acc.value.Mul(&acc.value, &t)
+		scalars[i] = acc.value
+	}
+
+	return acc.updates(elements, scalars, false), nil
+}
+
+// Remove removes the elements from the accumulator, and returns the corresponding updates.
+// If an element is not accumulated, nothing is removed.
+func (acc *Accumulator) Remove(elements ...fr.Element) ([]Update, error) {
+	removed := make(map[fr.Element]struct{}, len(elements))
+	for i := 0; i < len(elements); i++ {
+		if _, ok := removed[elements[i]]; ok || !acc.Contains(&elements[i]) {
+			return nil, ErrNotMember
+		}
+		removed[elements[i]] = struct{}{}
+	}
+
+	scalars := make([]fr.Element, len(elements))
+	var t fr.Element
+	for i := 0; i < len(elements); i++ {
+		delete(acc.elements, elements[i])
+		t.Add(&elements[i], &acc.secret).Inverse(&t)
+		acc.value.Mul(&acc.value, &t)
+		scalars[i] = acc.value
+	}
+
+	return acc.updates(elements, scalars, true), nil
+}
+
+// MembershipWitness returns the witness that x is accumulated
+func (acc *Accumulator) MembershipWitness(x fr.Element) (MembershipWitness, error) {
+	if !acc.Contains(&x) {
+		return MembershipWitness{}, ErrNotMember
+	}
+
+	// W = [value/(x + s)]G1
+	var w fr.Element
+	w.Add(&x, &acc.secret).Inverse(&w).Mul(&w, &acc.value)
+
+	res := MembershipWitness{Element: x}
+	res.W.ScalarMultiplication(&acc.PublicKey.G1, w.ToBigIntRegular(new(big.Int)))
+	return res, nil
+}
+
+// NonMembershipWitness returns the witness that y is not accumulated
+func (acc *Accumulator) NonMembershipWitness(y fr.Element) (NonMembershipWitness, error) {
+	if acc.Contains(&y) {
+		return NonMembershipWitness{}, ErrMember
+	}
+	var ys fr.Element
+	ys.Add(&y, &acc.secret)
+	if ys.IsZero() {
+		return NonMembershipWitness{}, ErrInvalidElement
+	}
+
+	res := NonMembershipWitness{Element: y}
+
+	// D = Prod_{x in X} (x - y)
+	res.D.SetOne()
+	var t fr.Element
+	for x := range acc.elements {
+		t.Sub(&x, &y)
+		res.D.Mul(&res.D, &t)
+	}
+
+	// W = [(value - D)/(y + s)]G1
+	var w fr.Element
+	w.Sub(&acc.value, &res.D).Mul(&w, ys.Inverse(&ys))
+	res.W.ScalarMultiplication(&acc.PublicKey.G1, w.ToBigIntRegular(new(big.Int)))
+
+	return res, nil
+}
+
+// VerifyMembership verifies that the witness proves that its element is accumulated in value
+func (pk *PublicKey) VerifyMembership(value *bls12377.G1Affine, witness *MembershipWitness) error {
+
+	// e(W, [Element + s]G2)*e(-Value, G2) == 1
+	var minusValue bls12377.G1Affine
+	minusValue.Neg(value)
+	return pk.pairingCheck(&witness.W, &witness.Element, &minusValue, ErrInvalidWitness)
+}
+
+// VerifyNonMembership verifies that the witness proves that its element is not accumulated in value
+func (pk *PublicKey) VerifyNonMembership(value *bls12377.G1Affine, witness *NonMembershipWitness) error {
+	if witness.D.IsZero() {
+		return ErrInvalidWitness
+	}
+
+	// e(W, [Element + s]G2)*e([D]G1 - Value, G2) == 1
+	var p, v bls12377.G1Jac
+	p.FromAffine(&pk.G1)
+	p.ScalarMultiplication(&p, witness.D.ToBigIntRegular(new(big.Int)))
+	v.FromAffine(value)
+	p.SubAssign(&v)
+	var pAff bls12377.G1Affine
+	pAff.FromJacobian(&p)
+	return pk.pairingCheck(&witness.W, &witness.Element, &pAff, ErrInvalidWitness)
+}
+
+// VerifyUpdate verifies that the update is consistent: e(New, [Element + s]G2) == e(Old, G2)
+// if Element is removed, e(Old, [Element + s]G2) == e(New, G2) if it is added.
+func (pk *PublicKey) VerifyUpdate(update *Update) error {
+	small, large := &update.Old, &update.New
+	if update.Removed {
+		small, large = large, small
+	}
+	var minusLarge bls12377.G1Affine
+	minusLarge.Neg(large)
+	return pk.pairingCheck(small, &update.Element, &minusLarge, ErrInvalidUpdate)
+}
+
+// Update updates the witness with the updates, applied in order, with a single multi exponentiation.
+// The updates must start at the value of the accumulator the witness was issued or last updated for.
+// The updates can't add or remove the element of the witness.
+func (witness *MembershipWitness) Update(updates []Update) error {
+	_, err := applyUpdates(&witness.W, &witness.Element, updates)
+	return err
+}
+
+// Update updates the witness with the updates, applied in order, with a single multi exponentiation.
+// The updates must start at the value of the accumulator the witness was issued or last updated for.
+// The updates can't add or remove the element of the witness.
+func (witness *NonMembershipWitness) Update(updates []Update) error {
+	factor, err := applyUpdates(&witness.W, &witness.Element, updates)
+	if err != nil {
+		return err
+	}
+	witness.D.Mul(&witness.D, &factor)
+	return nil
+}
+
+// applyUpdates updates the point w of the witness of e, and returns the factor applied to it.
+//
+// Adding z maps f(X) to f(X)*(X + z), and the witness W of e to W' = Old + (z - e)*W.
+// Removing z maps f(X) to f(X)/(X + z), and W to W' = (W - New)/(z - e).
+// This holds for both membership and non membership witnesses, and for the latter the constant
+// D is multiplied by the same factor as W: (z - e) or 1/(z - e).
+// After n updates W_n = (Prod_k a_k)*W_0 + Sum_k (Prod_{j>k} a_j)*b_k*P_k which is computed with one
+// multi exponentiation.
+func applyUpdates(w *bls12377.G1Affine, e *fr.Element, updates []Update) (fr.Element, error) {
+	n := len(updates)
+
+	// W_k = a_k*W_{k-1} + b_k*P_k
+	a := make([]fr.Element, n)
+	b := make([]fr.Element, n)
+	points := make([]bls12377.G1Affine, n+1)
+	for k := 0; k < n; k++ {
+		a[k].Sub(&updates[k].Element, e)
+		if a[k].IsZero() {
+			return fr.Element{}, ErrUpdatedElement
+		}
+		if updates[k].Removed {
+			points[k] = updates[k].New
+		} else {
+			points[k] = updates[k].Old
+			b[k].SetOne()
+		}
+	}
+	for k := 0; k < n; k++ {
+		if updates[k].Removed {
+			a[k].Inverse(&a[k])
+			b[k].Neg(&a[k])
+		}
+	}
+
+	scalars := make([]fr.Element, n+1)
+	var factor fr.Element
+	factor.SetOne()
+	for k := n - 1; k >= 0; k-- {
+		scalars[k].Mul(&b[k], &factor)
+		factor.Mul(&factor, &a[k])
+	}
+	points[n] = *w
+	scalars[n] = factor
+
+	w.MultiExp(points, toRegular(scalars))
+
+	return factor, nil
+}
+
+// pairingCheck checks e(p, [e + s]G2)*e(q, G2) == 1, and returns errInvalid otherwise
+func (pk *PublicKey) pairingCheck(p *bls12377.G1Affine, e *fr.Element, q *bls12377.G1Affine, errInvalid error) error {
+	var es bls12377.G2Jac
+	es.FromAffine(&pk.G2)
+	es.ScalarMultiplication(&es, e.ToBigIntRegular(new(big.Int)))
+	es.AddMixed(&pk.SG2)
+	var esAff bls12377.G2Affine
+	esAff.FromJacobian(&es)
+
+	ok, err := bls12377.PairingCheck(
+		[]bls12377.G1Affine{*p, *q},
+		[]bls12377.G2Affine{esAff, pk.G2},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errInvalid
+	}
+	return nil
+}
+
+// checkAdd checks that the elements are distinct, not accumulated, and not equal to -s
+func (acc *Accumulator) checkAdd(elements []fr.Element) error {
+	added := make(map[fr.Element]struct{}, len(elements))
+	var t fr.Element
+	for i := 0; i < len(elements); i++ {
+		if _, ok := added[elements[i]]; ok || acc.Contains(&elements[i]) {
+			return ErrMember
+		}
+		added[elements[i]] = struct{}{}
+		if t.Add(&elements[i], &acc.secret).IsZero() {
+			return ErrInvalidElement
+		}
+	}
+	return nil
+}
+
+// updates returns the updates for the elements, values[i] being the value of the
+// accumulator after the i-th element is added or removed, in scalar form
+func (acc *Accumulator) updates(elements, values []fr.Element, removed bool) []Update {
+	points := make([]bls12377.G1Affine, len(values))
+	if len(values) > 0 {
+		points = bls12377.BatchScalarMultiplicationG1(&acc.PublicKey.G1, toRegular(values))
+	}
+
+	res := make([]Update, len(elements))
+	old := acc.Value
+	for i := 0; i < len(elements); i++ {
+		res[i] = Update{Element: elements[i], Removed: removed, Old: old, New: points[i]}
+		old = points[i]
+	}
+	acc.Value = old
+
+	return res
+}
+
+// toRegular returns a copy of the scalars, converted from Montgomery form
+func toRegular(scalars []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(scalars))
+	parallel.Execute(len(scalars), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i] = scalars[i]
+			res[i].FromMont()
+		}
+	})
+	return res
+}
@@ -0,0 +1,336 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package accumulator
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// testSecret secret of the accumulators used in the tests, it should be random
+var testSecret = new(big.Int).SetUint64(42)
+
+func randomElements(n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func Example() {
+	// the manager accumulates the set {1, 2, 3}
+	elements := make([]fr.Element, 3)
+	for i := 0; i < 3; i++ {
+		elements[i].SetUint64(uint64(i + 1))
+	}
+	acc, _ := New(testSecret, elements)
+
+	// and issues a witness that 2 is in the set
+	witness, _ := acc.MembershipWitness(elements[1])
+
+	// anyone can check it with the public key
+	if err := acc.PublicKey.VerifyMembership(&acc.Value, &witness); err != nil {
+		fmt.Println("1. invalid witness")
+	} else {
+		fmt.Println("1. valid witness")
+	}
+
+	// 4 is added, the holder of the witness updates it
+	var four fr.Element
+	four.SetUint64(4)
+	updates, _ := acc.Add(four)
+	witness.Update(updates)
+	if err := acc.PublicKey.VerifyMembership(&acc.Value, &witness); err != nil {
+		fmt.Println("2. invalid witness")
+	} else {
+		fmt.Println("2. valid witness")
+	}
+
+	// Output:
+	// 1. valid witness
+	// 2. valid witness
+}
+
+func TestMembership(t *testing.T) {
+
+	elements := randomElements(16)
+	acc, err := New(testSecret, elements)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < len(elements); i++ {
+		witness, err := acc.MembershipWitness(elements[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := acc.PublicKey.VerifyMembership(&acc.Value, &witness); err != nil {
+			t.Fatal(err)
+		}
+
+		// witness for another element
+		witness.Element.Double(&witness.Element)
+		if err := acc.PublicKey.VerifyMembership(&acc.Value, &witness); err != ErrInvalidWitness {
+			t.Fatal("verifying a witness for another element should have failed")
+		}
+	}
+
+	other := randomElements(1)[0]
+	if _, err := acc.MembershipWitness(other); err != ErrNotMember {
+		t.Fatal("a witness for a non member should not be issued")
+	}
+	if _, err := New(testSecret, append(elements, elements[3])); err != ErrMember {
+		t.Fatal("duplicated elements should be rejected")
+	}
+
+	// the secret is the trapdoor
+	var minusSecret fr.Element
+	minusSecret.SetBigInt(testSecret).Neg(&minusSecret)
+	if _, err := acc.Add(minusSecret); err != ErrInvalidElement {
+		t.Fatal("-s should not be accumulated")
+	}
+}
+
+func TestNonMembership(t *testing.T) {
+
+	elements := randomElements(16)
+	acc, err := New(testSecret, elements)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	y := randomElements(1)[0]
+	witness, err := acc.NonMembershipWitness(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := acc.PublicKey.VerifyNonMembership(&acc.Value, &witness); err != nil {
+		t.Fatal(err)
+	}
+
+	// tampered witnesses
+	_witness := witness
+	_witness.D.Double(&_witness.D)
+	if err := acc.PublicKey.VerifyNonMembership(&acc.Value, &_witness); err != ErrInvalidWitness {
+		t.Fatal("verifying a tampered witness should have failed")
+	}
+	_witness.D.SetZero()
+	if err := acc.PublicKey.VerifyNonMembership(&acc.Value, &_witness); err != ErrInvalidWitness {
+		t.Fatal("verifying a witness with D = 0 should have failed")
+	}
+
+	if _, err := acc.NonMembershipWitness(elements[5]); err != ErrMember {
+		t.Fatal("a non membership witness for a member should not be issued")
+	}
+
+	// empty set
+	empty, err := New(testSecret, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witness, err = empty.NonMembershipWitness(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := empty.PublicKey.VerifyNonMembership(&empty.Value, &witness); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUpdates(t *testing.T) {
+
+	elements := randomElements(16)
+	acc, err := New(testSecret, elements[:8])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	member, err := acc.MembershipWitness(elements[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonMember, err := acc.NonMembershipWitness(elements[15])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// batch of additions and removals
+	var updates []Update
+	u, err := acc.Add(elements[8:12]...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates = append(updates, u...)
+	u, err = acc.Remove(elements[3], elements[9])
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates = append(updates, u...)
+	u, err = acc.Add(elements[12])
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates = append(updates, u...)
+
+	for i := 0; i < len(updates); i++ {
+		if err := acc.PublicKey.VerifyUpdate(&updates[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if acc.Len() != 11 || acc.Contains(&elements[9]) || !acc.Contains(&elements[12]) {
+		t.Fatal("wrong set after the updates")
+	}
+
+	// the updated witnesses match the ones issued by the manager
+	if err := member.Update(updates); err != nil {
+		t.Fatal(err)
+	}
+	if err := nonMember.Update(updates); err != nil {
+		t.Fatal(err)
+	}
+	expectedMember, err := acc.MembershipWitness(elements[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedNonMember, err := acc.NonMembershipWitness(elements[15])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(member, expectedMember) || !reflect.DeepEqual(nonMember, expectedNonMember) {
+		t.Fatal("updated witnesses don't match the issued ones")
+	}
+	if err := acc.PublicKey.VerifyMembership(&acc.Value, &member); err != nil {
+		t.Fatal(err)
+	}
+	if err := acc.PublicKey.VerifyNonMembership(&acc.Value, &nonMember); err != nil {
+		t.Fatal(err)
+	}
+
+	// the element of the witness is removed, or added
+	removal, err := acc.Remove(elements[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := member.Update(removal); err != ErrUpdatedElement {
+		t.Fatal("a witness of a removed element should not be updated")
+	}
+	addition, err := acc.Add(elements[15])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nonMember.Update(addition); err != ErrUpdatedElement {
+		t.Fatal("a non membership witness of an added element should not be updated")
+	}
+
+	// invalid operations
+	if _, err := acc.Remove(elements[0]); err != ErrNotMember {
+		t.Fatal("removing a non member should have failed")
+	}
+	if _, err := acc.Add(elements[1]); err != ErrMember {
+		t.Fatal("adding a member should have failed")
+	}
+
+	// forged update
+	forged := updates[0]
+	forged.New = forged.Old
+	if err := acc.PublicKey.VerifyUpdate(&forged); err != ErrInvalidUpdate {
+		t.Fatal("verifying a forged update should have failed")
+	}
+}
+
+func TestSerialization(t *testing.T) {
+
+	elements := randomElements(4)
+	acc, err := New(testSecret, elements)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := acc.PublicKey.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var pk PublicKey
+	if _, err := pk.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(pk, acc.PublicKey) {
+		t.Fatal("public key serialization failed")
+	}
+
+	member, err := acc.MembershipWitness(elements[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := member.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _member MembershipWitness
+	if _, err := _member.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(member, _member) {
+		t.Fatal("membership witness serialization failed")
+	}
+
+	nonMember, err := acc.NonMembershipWitness(randomElements(1)[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := nonMember.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _nonMember NonMembershipWitness
+	if _, err := _nonMember.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nonMember, _nonMember) {
+		t.Fatal("non membership witness serialization failed")
+	}
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	elements := randomElements(1 << 10)
+	acc, _ := New(testSecret, elements[:1])
+	witness, _ := acc.MembershipWitness(elements[0])
+	updates, _ := acc.Add(elements[1:]...)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := witness
+		w.Update(updates)
+	}
+}
+
+func BenchmarkVerifyMembership(b *testing.B) {
+	elements := randomElements(1 << 10)
+	acc, _ := New(testSecret, elements)
+	witness, _ := acc.MembershipWitness(elements[0])
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		acc.PublicKey.VerifyMembership(&acc.Value, &witness)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package accumulator provides a bilinear accumulator over bls12-377, following
+// Nguyen, "Accumulators from Bilinear Pairings and Applications" (CT-RSA 2005).
+//
+// A set X of elements of fr is accumulated into a single point of G1, [Prod_{x in X} (x + s)]G1,
+// s being the secret of the accumulator manager. Membership and non membership witnesses
+// have a constant size, and are verified with a single pairing check, using only the public key.
+// Unlike the Merkle tree of accumulator/merkletree, the witnesses can be updated by their
+// holders when elements are added or removed, without knowing the whole set.
+package accumulator
@@ -0,0 +1,73 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package accumulator
+
+import (
+	"io"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+)
+
+// WriteTo writes binary encoding of the public key
+func (pk *PublicKey) WriteTo(w io.Writer) (int64, error) {
+	return encode(w, &pk.G1, &pk.G2, &pk.SG2)
+}
+
+// ReadFrom decodes the public key from reader
+func (pk *PublicKey) ReadFrom(r io.Reader) (int64, error) {
+	return decode(r, &pk.G1, &pk.G2, &pk.SG2)
+}
+
+// WriteTo writes binary encoding of a MembershipWitness
+func (witness *MembershipWitness) WriteTo(w io.Writer) (int64, error) {
+	return encode(w, &witness.Element, &witness.W)
+}
+
+// ReadFrom decodes a MembershipWitness from reader
+func (witness *MembershipWitness) ReadFrom(r io.Reader) (int64, error) {
+	return decode(r, &witness.Element, &witness.W)
+}
+
+// WriteTo writes binary encoding of a NonMembershipWitness
+func (witness *NonMembershipWitness) WriteTo(w io.Writer) (int64, error) {
+	return encode(w, &witness.Element, &witness.W, &witness.D)
+}
+
+// ReadFrom decodes a NonMembershipWitness from reader
+func (witness *NonMembershipWitness) ReadFrom(r io.Reader) (int64, error) {
+	return decode(r, &witness.Element, &witness.W, &witness.D)
+}
+
+func encode(w io.Writer, toEncode ...interface{}) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+func decode(r io.Reader, toDecode ...interface{}) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	return dec.BytesRead(), nil
+}
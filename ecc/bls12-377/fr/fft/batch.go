@@ -0,0 +1,221 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fft
+
+import (
+	"math/bits"
+	"runtime"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// FFTBatch computes the discrete Fourier transform of each polynomial of a, as FFT(a[i], decimation, coset)
+// does, len(a[i]) being the cardinality of the domain.
+//
+// Instead of a tree of go routines per polynomial, the butterflies of the first stages and the independent
+// sub transforms of all the polynomials are scheduled as tasks sharing the twiddles and the coset table.
+// A task runs only once it holds a token of the semaphore: optionally, takes as parameter a ecc.CPUSemaphore
+// struct, which can be shared with MultiExp calls, to bound the number of CPUs used.
+func (domain *Domain) FFTBatch(a [][]fr.Element, decimation Decimation, coset uint64, opts ...*ecc.CPUSemaphore) {
+	var before scaleFunc
+	if coset != 0 {
+		// the input is in bit-reversed order if decimation == DIT
+		table, reversed := domain.cosetTable(domain.CosetTable, domain.CosetTableReversed, coset, decimation == DIT)
+		before = scaleByTable(table, reversed, nil)
+	}
+	domain.fftBatch(a, domain.Twiddles, decimation, before, nil, batchSemaphore(opts))
+}
+
+// FFTInverseBatch computes the inverse discrete Fourier transform of each polynomial of a, as
+// FFTInverse(a[i], decimation, coset) does, len(a[i]) being the cardinality of the domain.
+// The work is scheduled as in FFTBatch, optionally limited by the ecc.CPUSemaphore given as parameter.
+func (domain *Domain) FFTInverseBatch(a [][]fr.Element, decimation Decimation, coset uint64, opts ...*ecc.CPUSemaphore) {
+	var table []fr.Element
+	var reversed bool
+	if coset != 0 {
+		// the output is in bit-reversed order if decimation == DIF
+		table, reversed = domain.cosetTable(domain.CosetTableInv, domain.CosetTableInvReversed, coset, decimation == DIF)
+	}
+	after := scaleByTable(table, reversed, &domain.CardinalityInv)
+	domain.fftBatch(a, domain.TwiddlesInv, decimation, nil, after, batchSemaphore(opts))
+}
+
+// scaleFunc multiplies the elements start to end of p by a scaling factor
+type scaleFunc func(p []fr.Element, start, end int)
+
+// fftBatch computes the transforms of the polynomials of a: the first s stages (last s stages if decimation == DIT)
+// are split into 2**s tasks of butterflies per polynomial, the stages below into 2**s sub transforms. before
+// (resp. after), if not nil, scales each element of a polynomial by the first (resp. the last) task writing it.
+func (domain *Domain) fftBatch(a [][]fr.Element, twiddles [][]fr.Element, decimation Decimation, before, after scaleFunc, opt *ecc.CPUSemaphore) {
+	n := int(domain.Cardinality)
+	for i := 0; i < len(a); i++ {
+		if len(a[i]) != n {
+			panic("the size of the polynomials must be the cardinality of the domain")
+		}
+	}
+	if decimation != DIF && decimation != DIT {
+		panic("not implemented")
+	}
+
+	s := batchSplits(len(a), n, cap(opt.ChCPU))
+	nbChunks := 1 << s
+	chunkSize := n >> s
+
+	// sub transforms of size n/2**s, from the stage s. If s == 0, they are the whole transforms.
+	subFFTs := func(p []fr.Element, chunk int) {
+		start, end := chunk*chunkSize, (chunk+1)*chunkSize
+		if (decimation == DIT || s == 0) && before != nil {
+			before(p, start, end)
+		}
+		if decimation == DIF {
+			difFFT(p[start:end], twiddles, s, -1, nil)
+		} else {
+			ditFFT(p[start:end], twiddles, s, -1, nil)
+		}
+		if (decimation == DIF || s == 0) && after != nil {
+			after(p, start, end)
+		}
+	}
+
+	// butterflies chunk*chunkSize/2 to (chunk+1)*chunkSize/2 of the stage
+	stageButterflies := func(stage int) func(p []fr.Element, chunk int) {
+		return func(p []fr.Element, chunk int) {
+			start, end := chunk*chunkSize/2, (chunk+1)*chunkSize/2
+			// at the stage 0, the butterflies from start to end write the elements start to end and n/2+start to n/2+end
+			if stage == 0 && decimation == DIF && before != nil {
+				before(p, start, end)
+				before(p, n/2+start, n/2+end)
+			}
+			butterflies(p, twiddles, stage, start, end, decimation)
+			if stage == 0 && decimation == DIT && after != nil {
+				after(p, start, end)
+				after(p, n/2+start, n/2+end)
+			}
+		}
+	}
+
+	if decimation == DIF {
+		for stage := 0; stage < s; stage++ {
+			batchExecute(opt, a, nbChunks, stageButterflies(stage))
+		}
+		batchExecute(opt, a, nbChunks, subFFTs)
+	} else {
+		batchExecute(opt, a, nbChunks, subFFTs)
+		for stage := s - 1; stage >= 0; stage-- {
+			batchExecute(opt, a, nbChunks, stageButterflies(stage))
+		}
+	}
+}
+
+// butterflies computes the butterflies start to end of the stage of the transform of a, the stage
+// having len(a)/2 butterflies on pairs of elements at distance m = len(a) >> (stage + 1)
+func butterflies(a []fr.Element, twiddles [][]fr.Element, stage, start, end int, decimation Decimation) {
+	m := len(a) >> (stage + 1)
+	var t, tm fr.Element
+	for i := start; i < end; i++ {
+		// the i-th butterfly is the k-th one of the block i/m, of size 2m
+		k := i & (m - 1)
+		j := 2*(i-k) + k
+		if decimation == DIF {
+			t = a[j]
+			a[j].Add(&a[j], &a[j+m])
+			a[j+m].
+				Sub(&t, &a[j+m]).
+				Mul(&a[j+m], &twiddles[stage][k])
+		} else {
+			t = a[j]
+			tm.Mul(&a[j+m], &twiddles[stage][k])
+			a[j].Add(&a[j], &tm)
+			a[j+m].Sub(&t, &tm)
+		}
+	}
+}
+
+// batchExecute runs task(a[i], chunk) for each polynomial and each chunk < nbChunks, each on its own go routine
+// once it holds a token of the semaphore, and waits for all of them
+func batchExecute(opt *ecc.CPUSemaphore, a [][]fr.Element, nbChunks int, task func(p []fr.Element, chunk int)) {
+	var wg sync.WaitGroup
+
+	// schedule all our tasks before other calls sharing the semaphore
+	opt.Lock.Lock()
+	for i := 0; i < len(a); i++ {
+		for chunk := 0; chunk < nbChunks; chunk++ {
+			<-opt.ChCPU // wait to have a cpu before scheduling
+			wg.Add(1)
+			go func(p []fr.Element, chunk int) {
+				task(p, chunk)
+				opt.ChCPU <- struct{}{} // release token in the semaphore
+				wg.Done()
+			}(a[i], chunk)
+		}
+	}
+	opt.Lock.Unlock()
+
+	wg.Wait()
+}
+
+// batchSplits returns the number s of stages split into 2**s tasks per polynomial, the smallest one
+// giving at least a task per CPU, unless the tasks would have less than butterflyThreshold butterflies
+func batchSplits(nbPolynomials, n, nbCPU int) int {
+	s := 0
+	for (nbPolynomials<<s) < nbCPU && (n>>(s+2)) >= butterflyThreshold {
+		s++
+	}
+	return s
+}
+
+func batchSemaphore(opts []*ecc.CPUSemaphore) *ecc.CPUSemaphore {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return ecc.NewCPUSemaphore(runtime.NumCPU())
+}
+
+// cosetTable returns the table of the coset, in bit-reversed order if bitReversed, with true if its
+// indices must be bit-reversed, the table in bit-reversed order being not precomputed
+func (domain *Domain) cosetTable(tables, tablesReversed [][]fr.Element, coset uint64, bitReversed bool) ([]fr.Element, bool) {
+	if !bitReversed {
+		return tables[coset-1], false
+	}
+	if domain.PrecomputeReversedTable != 0 {
+		return tablesReversed[coset-1], false
+	}
+	return tables[coset-1], true
+}
+
+// scaleByTable returns the scaleFunc multiplying p[i] by table[i], or by table[bitReverse(i)] if reversed,
+// and by factor if not nil. table may be nil.
+func scaleByTable(table []fr.Element, reversed bool, factor *fr.Element) scaleFunc {
+	return func(p []fr.Element, start, end int) {
+		nn := uint64(64 - bits.TrailingZeros64(uint64(len(p))))
+		for i := start; i < end; i++ {
+			if table != nil {
+				j := i
+				if reversed {
+					j = int(bits.Reverse64(uint64(i)) >> nn)
+				}
+				p[i].Mul(&p[i], &table[j])
+			}
+			if factor != nil {
+				p[i].Mul(&p[i], factor)
+			}
+		}
+	}
+}
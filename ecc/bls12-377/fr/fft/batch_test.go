@@ -0,0 +1,114 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fft
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func TestFFTBatch(t *testing.T) {
+
+	// with 1 CPU the polynomials are transformed in one task each, with 16 CPUs the first stages are split
+	for _, nbCPU := range []int{1, 16} {
+		for _, size := range []uint64{2, 1 << 6, 1 << 10} {
+			for _, precompute := range []bool{false, true} {
+				domain := NewDomain(size, 2, precompute)
+				opt := ecc.NewCPUSemaphore(nbCPU)
+
+				for _, decimation := range []Decimation{DIF, DIT} {
+					for _, coset := range []uint64{0, 2} {
+						pols := make([][]fr.Element, 3)
+						expected := make([][]fr.Element, len(pols))
+						for i := range pols {
+							pols[i] = make([]fr.Element, size)
+							expected[i] = make([]fr.Element, size)
+							for j := uint64(0); j < size; j++ {
+								pols[i][j].SetRandom()
+							}
+							copy(expected[i], pols[i])
+						}
+
+						for i := range expected {
+							domain.FFT(expected[i], decimation, coset)
+						}
+						domain.FFTBatch(pols, decimation, coset, opt)
+						for i := range pols {
+							for j := uint64(0); j < size; j++ {
+								if !pols[i][j].Equal(&expected[i][j]) {
+									t.Fatalf("FFTBatch of size %d doesn't match FFT", size)
+								}
+							}
+						}
+
+						for i := range expected {
+							domain.FFTInverse(expected[i], decimation, coset)
+						}
+						domain.FFTInverseBatch(pols, decimation, coset, opt)
+						for i := range pols {
+							for j := uint64(0); j < size; j++ {
+								if !pols[i][j].Equal(&expected[i][j]) {
+									t.Fatalf("FFTInverseBatch of size %d doesn't match FFTInverse", size)
+								}
+							}
+						}
+					}
+				}
+
+				// all the tokens are back in the semaphore
+				if len(opt.ChCPU) != nbCPU {
+					t.Fatal("the tokens of the semaphore should be released")
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkFFTBatch(b *testing.B) {
+
+	const nbPolynomials = 16
+
+	for i := 10; i < 18; i += 2 {
+		sizeDomain := 1 << i
+		domain := NewDomain(uint64(sizeDomain), 0, false)
+		pols := make([][]fr.Element, nbPolynomials)
+		for j := range pols {
+			pols[j] = make([]fr.Element, sizeDomain)
+			for k := range pols[j] {
+				pols[j][k].SetRandom()
+			}
+		}
+
+		b.Run(strconv.Itoa(nbPolynomials)+" fft 2**"+strconv.Itoa(i)+"bits", func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				for k := range pols {
+					domain.FFT(pols[k], DIT, 0)
+				}
+			}
+		})
+		b.Run(strconv.Itoa(nbPolynomials)+" fft 2**"+strconv.Itoa(i)+"bits (batch)", func(b *testing.B) {
+			for j := 0; j < b.N; j++ {
+				domain.FFTBatch(pols, DIT, 0)
+			}
+		})
+	}
+
+}
@@ -17,6 +17,7 @@
 package fft
 
 import (
+	"errors"
 	"io"
 	"math/big"
 	"math/bits"
@@ -76,13 +77,27 @@ type Domain struct {
 // * NewDomain(m, 2, false) outputs a new domain to perform fft on Z/mZ, plus a primitive
 // 2**2*m=4m-th root of 1 and associated data to compute fft/fftinv on the cosets of
 // (Z/4mZ)/(Z/mZ).
+//
+// NewDomain panics if the required roots of unity don't exist, NewDomainE returns an error instead.
 func NewDomain(m, depth uint64, precomputeReversedTable bool) *Domain {
+	domain, err := NewDomainE(m, depth, precomputeReversedTable)
+	if err != nil {
+		panic(err)
+	}
+	return domain
+}
 
-	// generator of the largest 2-adic subgroup
-	var rootOfUnity fr.Element
+var (
+	ErrDomainTooLarge = errors.New("m is too big: the required root of unity does not exist")
+	ErrCosetsTooLarge = errors.New("log(m) + cosets is too big: the required root of unity does not exist")
+)
 
-	rootOfUnity.SetString("8065159656716812877374967518403273466521432693661810619979959746626482506078")
-	const maxOrderRoot uint64 = 47
+// NewDomainE returns the domain built by NewDomain, or ErrDomainTooLarge (resp. ErrCosetsTooLarge)
+// if fr has no root of unity of order nextPowerOfTwo(m) (resp. (2**depth)*nextPowerOfTwo(m)).
+func NewDomainE(m, depth uint64, precomputeReversedTable bool) (*Domain, error) {
+
+	// generator of the largest 2-adic subgroup
+	rootOfUnity, maxOrderRoot := twoAdicRootOfUnity()
 
 	domain := &Domain{}
 	x := nextPowerOfTwo(m)
@@ -95,11 +110,11 @@ func NewDomain(m, depth uint64, precomputeReversedTable bool) *Domain {
 	// find generator for Z/2^(log(m))Z  and Z/2^(log(m)+cosets)Z
 	logx := uint64(bits.TrailingZeros64(x))
 	if logx > maxOrderRoot {
-		panic("m is too big: the required root of unity does not exist")
+		return nil, ErrDomainTooLarge
 	}
 	logGen := logx + depth
 	if logGen > maxOrderRoot {
-		panic("log(m) + cosets is too big: the required root of unity does not exist")
+		return nil, ErrCosetsTooLarge
 	}
 
 	expo := uint64(1 << (maxOrderRoot - logGen))
@@ -122,7 +137,17 @@ func NewDomain(m, depth uint64, precomputeReversedTable bool) *Domain {
 		domain.reverseCosetTables()
 	}
 
-	return domain
+	return domain, nil
+}
+
+// twoAdicRootOfUnity returns the generator of the largest 2-adic subgroup of fr, and the log2 of its order
+func twoAdicRootOfUnity() (fr.Element, uint64) {
+	var rootOfUnity fr.Element
+
+	rootOfUnity.SetString("8065159656716812877374967518403273466521432693661810619979959746626482506078")
+	const maxOrderRoot uint64 = 47
+
+	return rootOfUnity, maxOrderRoot
 }
 
 func (d *Domain) reverseCosetTables() {
@@ -188,7 +213,7 @@ func (d *Domain) preComputeTwiddles() {
 		cosetGensInv[0].Set(&d.FinerGeneratorInv)
 		for i := 1; i < nbCosets; i++ {
 			cosetGens[i].Mul(&cosetGens[i-1], &d.FinerGenerator)
-			cosetGensInv[i].Mul(&cosetGensInv[1], &d.FinerGeneratorInv)
+			cosetGensInv[i].Mul(&cosetGensInv[i-1], &d.FinerGeneratorInv)
 		}
 		wg.Add(2 + 2*nbCosets)
 		go twiddles(d.Twiddles, d.Generator)
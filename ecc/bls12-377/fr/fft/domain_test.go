@@ -45,3 +45,25 @@ func TestDomainSerialization(t *testing.T) {
 		t.Fatal("Domain.SetBytes(Bytes()) failed")
 	}
 }
+
+func TestNewDomainE(t *testing.T) {
+
+	_, maxOrderRoot := twoAdicRootOfUnity()
+
+	if _, err := NewDomainE(1<<6, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDomainE(1<<maxOrderRoot+1, 0, false); err != ErrDomainTooLarge {
+		t.Fatal("a domain larger than the largest 2-adic subgroup should be rejected")
+	}
+	if _, err := NewDomainE(1<<(maxOrderRoot-1), 2, false); err != ErrCosetsTooLarge {
+		t.Fatal("cosets outside of the largest 2-adic subgroup should be rejected")
+	}
+
+	defer func() {
+		if recover() != ErrDomainTooLarge {
+			t.Fatal("NewDomain should panic with ErrDomainTooLarge")
+		}
+	}()
+	NewDomain(1<<maxOrderRoot+1, 0, false)
+}
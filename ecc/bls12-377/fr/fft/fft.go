@@ -17,6 +17,7 @@
 package fft
 
 import (
+	"math/big"
 	"math/bits"
 	"runtime"
 
@@ -161,6 +162,46 @@ func (domain *Domain) FFTInverse(a []fr.Element, decimation Decimation, coset ui
 
 }
 
+// FFTShift computes the discrete Fourier transform of a on the coset shift*<Generator>, and stores
+// the result in a: with a in natural order, the i-th evaluation is at shift*Generator**i.
+// Unlike FFT, the coset is not restricted to the ones of the FinerGenerator precomputed by NewDomain:
+// the powers of shift are computed on the fly, with no precomputed table.
+// if decimation == DIT (decimation in time), the input must be in bit-reversed order
+// if decimation == DIF (decimation in frequency), the output will be in bit-reversed order
+func (domain *Domain) FFTShift(a []fr.Element, decimation Decimation, shift fr.Element) {
+	scaleByPowers(a, shift, decimation == DIT)
+	domain.FFT(a, decimation, 0)
+}
+
+// FFTInverseShift computes the inverse discrete Fourier transform of a on the coset shift*<Generator>,
+// and stores the result in a. shift must be non zero.
+// if decimation == DIT (decimation in time), the input must be in bit-reversed order
+// if decimation == DIF (decimation in frequency), the output will be in bit-reversed order
+func (domain *Domain) FFTInverseShift(a []fr.Element, decimation Decimation, shift fr.Element) {
+	domain.FFTInverse(a, decimation, 0)
+	var shiftInv fr.Element
+	shiftInv.Inverse(&shift)
+	scaleByPowers(a, shiftInv, decimation == DIF)
+}
+
+// scaleByPowers multiplies a[i] by shift**i, or by shift**bitReverse(i) if a is in bit-reversed order
+func scaleByPowers(a []fr.Element, shift fr.Element, bitReversed bool) {
+	n := uint64(len(a))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+	parallel.Execute(len(a), func(start, end int) {
+		var w fr.Element
+		w.Exp(shift, new(big.Int).SetUint64(uint64(start)))
+		for i := start; i < end; i++ {
+			j := uint64(i)
+			if bitReversed {
+				j = bits.Reverse64(j) >> nn
+			}
+			a[j].Mul(&a[j], &w)
+			w.Mul(&w, &shift)
+		}
+	})
+}
+
 func difFFT(a []fr.Element, twiddles [][]fr.Element, stage, maxSplits int, chDone chan struct{}) {
 	if chDone != nil {
 		defer func() {
@@ -293,10 +293,135 @@ func TestFFT(t *testing.T) {
 		},
 	))
 
+	properties.Property("DIF FFT on an arbitrary coset should be consistent with dual basis", prop.ForAll(
+
+		// checks that a random evaluation of a dual function eval(shift*gen**ithpower) is consistent with the FFT result
+		func(ithpower int) bool {
+
+			pol := make([]fr.Element, maxSize)
+			backupPol := make([]fr.Element, maxSize)
+
+			for i := 0; i < maxSize; i++ {
+				pol[i].SetRandom()
+			}
+			copy(backupPol, pol)
+
+			var shift fr.Element
+			shift.SetRandom()
+			domainWOPrecompute.FFTShift(pol, DIF, shift)
+			BitReverse(pol)
+
+			sample := domainWOPrecompute.Generator
+			sample.Exp(sample, big.NewInt(int64(ithpower))).
+				Mul(&sample, &shift)
+
+			eval := evaluatePolynomial(backupPol, sample)
+
+			return eval.Equal(&pol[ithpower])
+
+		},
+		gen.IntRange(0, maxSize-1),
+	))
+
+	properties.Property("FFT on an arbitrary coset should match FFT on the cosets of the FinerGenerator", prop.ForAll(
+
+		func() bool {
+
+			pol := make([]fr.Element, maxSize)
+			backupPol := make([]fr.Element, maxSize)
+
+			for i := 0; i < maxSize; i++ {
+				pol[i].SetRandom()
+			}
+			copy(backupPol, pol)
+
+			domainWOPrecompute.FFTShift(pol, DIT, domainWOPrecompute.FinerGenerator)
+			domainWOPrecompute.FFT(backupPol, DIT, 1)
+
+			check := true
+			for i := 0; i < len(pol); i++ {
+				check = check && (pol[i] == backupPol[i])
+			}
+			return check
+		},
+	))
+
+	properties.Property("bitReverse(DIF FFT(DIT FFT (bitReverse))))==id on an arbitrary coset", prop.ForAll(
+
+		func() bool {
+
+			pol := make([]fr.Element, maxSize)
+			backupPol := make([]fr.Element, maxSize)
+
+			for i := 0; i < maxSize; i++ {
+				pol[i].SetRandom()
+			}
+			copy(backupPol, pol)
+
+			var shift fr.Element
+			shift.SetRandom()
+			BitReverse(pol)
+			domainWOPrecompute.FFTShift(pol, DIT, shift)
+			domainWOPrecompute.FFTInverseShift(pol, DIF, shift)
+			BitReverse(pol)
+
+			check := true
+			for i := 0; i < len(pol); i++ {
+				check = check && (pol[i] == backupPol[i])
+			}
+			return check
+		},
+	))
+
+	properties.Property("DIT FFT(DIF FFT)==id on an arbitrary coset", prop.ForAll(
+
+		func() bool {
+
+			pol := make([]fr.Element, maxSize)
+			backupPol := make([]fr.Element, maxSize)
+
+			for i := 0; i < maxSize; i++ {
+				pol[i].SetRandom()
+			}
+			copy(backupPol, pol)
+
+			var shift fr.Element
+			shift.SetRandom()
+			domainWOPrecompute.FFTInverseShift(pol, DIF, shift)
+			domainWOPrecompute.FFTShift(pol, DIT, shift)
+
+			check := true
+			for i := 0; i < len(pol); i++ {
+				check = check && (pol[i] == backupPol[i])
+			}
+			return check
+		},
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
 }
 
+func TestCosetTables(t *testing.T) {
+
+	domain := NewDomain(1<<4, 2, false)
+	one := fr.One()
+
+	// CosetTable[i][j] = FinerGenerator**((i+1)*j), and CosetTableInv[i][j] is its inverse
+	for i := 0; i < len(domain.CosetTable); i++ {
+		var g fr.Element
+		g.Exp(domain.FinerGenerator, big.NewInt(int64(i+1)))
+		for j := 0; j < len(domain.CosetTable[i]); j++ {
+			var e, p fr.Element
+			e.Exp(g, big.NewInt(int64(j)))
+			p.Mul(&domain.CosetTable[i][j], &domain.CosetTableInv[i][j])
+			if !e.Equal(&domain.CosetTable[i][j]) || !p.Equal(&one) {
+				t.Fatal("wrong coset tables")
+			}
+		}
+	}
+}
+
 // --------------------------------------------------------------------
 // benches
 func BenchmarkBitReverse(b *testing.B) {
@@ -357,6 +482,16 @@ func BenchmarkFFT(b *testing.B) {
 				domain.FFT(_pol, DIT, 1)
 			}
 		})
+		b.Run("fft 2**"+strconv.Itoa(i)+"bits (arbitrary coset)", func(b *testing.B) {
+			copy(_pol, pol)
+			domain := NewDomain(uint64(sizeDomain), 0, false)
+			var shift fr.Element
+			shift.SetRandom()
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				domain.FFTShift(_pol, DIT, shift)
+			}
+		})
 	}
 
 }
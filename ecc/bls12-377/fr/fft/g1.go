@@ -0,0 +1,153 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fft
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+)
+
+// FFTG1 computes the discrete Fourier transform of a, whose coefficients are points of G1, and stores
+// the result in a: a[i] is replaced by Sum_j [w**(i*j)]a[j], w being the generator of the domain.
+// len(a) must be the cardinality of the domain, FFTG1 panics otherwise.
+// if decimation == DIT (decimation in time), the input must be in bit-reversed order
+// if decimation == DIF (decimation in frequency), the output will be in bit-reversed order
+func (domain *Domain) FFTG1(a []curve.G1Jac, decimation Decimation) {
+	domain.checkSizeG1(a)
+	fftG1(a, domain.Twiddles, decimation)
+}
+
+// FFTInverseG1 computes the inverse discrete Fourier transform of a, whose coefficients are points of G1,
+// and stores the result in a. len(a) must be the cardinality of the domain, FFTInverseG1 panics otherwise.
+// if decimation == DIT (decimation in time), the input must be in bit-reversed order
+// if decimation == DIF (decimation in frequency), the output will be in bit-reversed order
+func (domain *Domain) FFTInverseG1(a []curve.G1Jac, decimation Decimation) {
+	domain.checkSizeG1(a)
+	fftG1(a, domain.TwiddlesInv, decimation)
+
+	var cardinalityInv big.Int
+	domain.CardinalityInv.ToBigIntRegular(&cardinalityInv)
+	parallel.Execute(len(a), func(start, end int) {
+		for i := start; i < end; i++ {
+			a[i].ScalarMultiplication(&a[i], &cardinalityInv)
+		}
+	})
+}
+
+// ToLagrangeG1 converts the SRS ([alpha**j]G1)_{j<n}, n being the cardinality of the domain, to the
+// Lagrange basis ([L_i(alpha)]G1)_{i<n}, L_i being the Lagrange polynomial of the point w**i of the domain.
+// L_i(X) = 1/n*Sum_j (X/w**i)**j so [L_i(alpha)]G1 is the i-th coefficient of the inverse FFT of the SRS.
+// len(srs) must be at least the cardinality of the domain, ToLagrangeG1 panics otherwise; the points
+// after the first n are ignored.
+func (domain *Domain) ToLagrangeG1(srs []curve.G1Affine) []curve.G1Affine {
+	n := int(domain.Cardinality)
+	if len(srs) < n {
+		panic("the size of the SRS must be at least the cardinality of the domain")
+	}
+
+	a := make([]curve.G1Jac, n)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			a[i].FromAffine(&srs[i])
+		}
+	})
+	domain.FFTInverseG1(a, DIF)
+	BitReverseG1(a)
+
+	res := make([]curve.G1Affine, n)
+	curve.BatchJacobianToAffineG1(a, res)
+	return res
+}
+
+// checkSizeG1 panics if the size of a is not the cardinality of the domain
+func (domain *Domain) checkSizeG1(a []curve.G1Jac) {
+	if uint64(len(a)) != domain.Cardinality {
+		panic("the size of a must be the cardinality of the domain")
+	}
+}
+
+// fftG1 computes in place the FFT of a, whose coefficients are points of G1, with the twiddles
+// of a Domain of size len(a), stage by stage with parallel butterflies
+func fftG1(a []curve.G1Jac, twiddles [][]fr.Element, decimation Decimation) {
+	n := len(a)
+	nbStages := bits.TrailingZeros(uint(n))
+
+	switch decimation {
+	case DIF:
+		for stage := 0; stage < nbStages; stage++ {
+			butterfliesG1(a, twiddles, stage, decimation)
+		}
+	case DIT:
+		for stage := nbStages - 1; stage >= 0; stage-- {
+			butterfliesG1(a, twiddles, stage, decimation)
+		}
+	default:
+		panic("not implemented")
+	}
+}
+
+// butterfliesG1 computes in parallel the len(a)/2 butterflies of the stage, on pairs of points
+// at distance m = len(a) >> (stage + 1). The scalar multiplication by the twiddle 1 is skipped.
+func butterfliesG1(a []curve.G1Jac, twiddles [][]fr.Element, stage int, decimation Decimation) {
+	m := len(a) >> (stage + 1)
+	parallel.Execute(len(a)/2, func(start, end int) {
+		var bi big.Int
+		var t curve.G1Jac
+		for i := start; i < end; i++ {
+			// the i-th butterfly is the k-th one of the block i/m, of size 2m
+			k := i & (m - 1)
+			j := 2*(i-k) + k
+			if decimation == DIF {
+				t = a[j]
+				a[j].AddAssign(&a[j+m])
+				a[j+m].Neg(&a[j+m]).AddAssign(&t)
+				if k != 0 {
+					twiddles[stage][k].ToBigIntRegular(&bi)
+					a[j+m].ScalarMultiplication(&a[j+m], &bi)
+				}
+			} else {
+				if k != 0 {
+					twiddles[stage][k].ToBigIntRegular(&bi)
+					a[j+m].ScalarMultiplication(&a[j+m], &bi)
+				}
+				t = a[j]
+				a[j].AddAssign(&a[j+m])
+				a[j+m].Neg(&a[j+m]).AddAssign(&t)
+			}
+		}
+	})
+}
+
+// BitReverseG1 applies the bit-reversal permutation to a.
+// len(a) must be a power of 2
+func BitReverseG1(a []curve.G1Jac) {
+	n := uint64(len(a))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fft
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+)
+
+// randomPointsG1 returns n points [s_i]G1 and the random scalars s_i
+func randomPointsG1(n int) ([]curve.G1Jac, []fr.Element) {
+	g1, _, _, _ := curve.Generators()
+	points := make([]curve.G1Jac, n)
+	scalars := make([]fr.Element, n)
+	var bi big.Int
+	for i := 0; i < n; i++ {
+		scalars[i].SetRandom()
+		scalars[i].ToBigIntRegular(&bi)
+		points[i].ScalarMultiplication(&g1, &bi)
+	}
+	return points, scalars
+}
+
+func TestFFTG1(t *testing.T) {
+
+	const size = 16
+	domain := NewDomain(size, 0, false)
+	g1, _, _, _ := curve.Generators()
+
+	// the FFT of [s_i]G1 is [FFT(s)_i]G1
+	checkFFT := func(points []curve.G1Jac, scalars []fr.Element) {
+		var bi big.Int
+		var expected curve.G1Jac
+		for i := 0; i < size; i++ {
+			scalars[i].ToBigIntRegular(&bi)
+			expected.ScalarMultiplication(&g1, &bi)
+			if !expected.Equal(&points[i]) {
+				t.Fatal("the FFT of the points should be the points of the FFT of the scalars")
+			}
+		}
+	}
+
+	for _, decimation := range []Decimation{DIF, DIT} {
+		points, scalars := randomPointsG1(size)
+
+		domain.FFTG1(points, decimation)
+		domain.FFT(scalars, decimation, 0)
+		checkFFT(points, scalars)
+
+		domain.FFTInverseG1(points, decimation)
+		domain.FFTInverse(scalars, decimation, 0)
+		checkFFT(points, scalars)
+	}
+
+	points, scalars := randomPointsG1(size)
+	BitReverseG1(points)
+	BitReverse(scalars)
+	checkFFT(points, scalars)
+}
+
+func TestToLagrangeG1(t *testing.T) {
+
+	const size = 16
+	domain := NewDomain(size, 0, false)
+	_, _, g1, _ := curve.Generators()
+
+	// SRS [alpha**j]G1, with one more point which is ignored
+	var alpha fr.Element
+	alpha.SetRandom()
+	srs := make([]curve.G1Affine, size+1)
+	var bi big.Int
+	var x fr.Element
+	x.SetOne()
+	for j := 0; j < len(srs); j++ {
+		x.ToBigIntRegular(&bi)
+		srs[j].ScalarMultiplication(&g1, &bi)
+		x.Mul(&x, &alpha)
+	}
+
+	lagrange := domain.ToLagrangeG1(srs)
+	if len(lagrange) != size {
+		t.Fatal("the Lagrange basis should have a point per point of the domain")
+	}
+
+	// L_i(alpha) = w**i/n * (alpha**n - 1)/(alpha - w**i)
+	var w, one, zn, l fr.Element
+	one.SetOne()
+	w.SetOne()
+	zn.Exp(alpha, big.NewInt(size)).Sub(&zn, &one).Mul(&zn, &domain.CardinalityInv)
+	for i := 0; i < size; i++ {
+		l.Sub(&alpha, &w).Inverse(&l).Mul(&l, &zn).Mul(&l, &w)
+		l.ToBigIntRegular(&bi)
+		var expected curve.G1Affine
+		expected.ScalarMultiplication(&g1, &bi)
+		if !expected.Equal(&lagrange[i]) {
+			t.Fatalf("wrong Lagrange basis at w**%d", i)
+		}
+		w.Mul(&w, &domain.Generator)
+	}
+}
+
+func TestFFTG1Sizes(t *testing.T) {
+
+	const size = 16
+	domain := NewDomain(size, 0, false)
+
+	// assertPanics checks that f panics, the size of its input not matching the domain
+	assertPanics := func(name string, f func()) {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("%s should panic on an input whose size doesn't match the domain", name)
+			}
+		}()
+		f()
+	}
+
+	for _, n := range []int{size / 2, size + 1, 2 * size} {
+		points := make([]curve.G1Jac, n)
+		assertPanics("FFTG1", func() { domain.FFTG1(points, DIF) })
+		assertPanics("FFTInverseG1", func() { domain.FFTInverseG1(points, DIT) })
+	}
+	assertPanics("ToLagrangeG1", func() { domain.ToLagrangeG1(make([]curve.G1Affine, size-1)) })
+}
+
+func BenchmarkFFTG1(b *testing.B) {
+
+	const maxSize = 1 << 12
+
+	points, _ := randomPointsG1(maxSize)
+
+	for i := 8; i <= 12; i += 2 {
+		sizeDomain := 1 << i
+		_points := make([]curve.G1Jac, sizeDomain)
+		b.Run("fft G1 2**"+strconv.Itoa(i)+"bits", func(b *testing.B) {
+			copy(_points, points)
+			domain := NewDomain(uint64(sizeDomain), 0, false)
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				domain.FFTG1(_points, DIT)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,238 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fft
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// ErrUnsupportedSize is returned when no subgroup of fr of size 2**a * 3**b is large enough
+var ErrUnsupportedSize = errors.New("no multiplicative subgroup of fr of size 2**a * 3**b is large enough")
+
+// MixedRadixDomain subgroup of fr with a cardinality 2**a * 3**b, on which the FFT is computed
+// with radix 2 and radix 3 butterflies. Its sizes are between the powers of 2 to which the
+// cardinality of a Domain is rounded up, saving up to half of the memory of the evaluations.
+type MixedRadixDomain struct {
+	Cardinality    uint64
+	Log2, Log3     uint64 // Cardinality = 2**Log2 * 3**Log3
+	CardinalityInv fr.Element
+	Generator      fr.Element
+	GeneratorInv   fr.Element
+
+	// twiddles[i] = Generator**i and twiddlesInv[i] = GeneratorInv**i, for i < Cardinality
+	twiddles, twiddlesInv []fr.Element
+
+	// twiddles of the radix 2 FFTs of size 2**a, on the subgroup generated by Generator**(3**b)
+	radix2 *Domain
+}
+
+// NewMixedRadixDomain returns the subgroup of fr of smallest cardinality 2**a * 3**b >= m.
+// a and b are bounded by the 2-adicity and the 3-adicity of fr, ErrUnsupportedSize is returned
+// if m is larger than all the subgroups of this form.
+func NewMixedRadixDomain(m uint64) (*MixedRadixDomain, error) {
+	root2, maxOrder2 := twoAdicRootOfUnity()
+	root3, maxOrder3 := threeAdicRootOfUnity()
+
+	log2, log3, ok := mixedRadixSize(m, maxOrder2, maxOrder3)
+	if !ok {
+		return nil, ErrUnsupportedSize
+	}
+
+	domain := &MixedRadixDomain{
+		Cardinality: (uint64(1) << log2) * pow3(log3),
+		Log2:        log2,
+		Log3:        log3,
+	}
+
+	// the product of elements of orders 2**a and 3**b has order 2**a * 3**b
+	var g2, g3 fr.Element
+	g2.Exp(root2, new(big.Int).SetUint64(uint64(1)<<(maxOrder2-log2)))
+	g3.Exp(root3, new(big.Int).SetUint64(pow3(maxOrder3-log3)))
+	domain.Generator.Mul(&g2, &g3)
+	domain.GeneratorInv.Inverse(&domain.Generator)
+	domain.CardinalityInv.SetUint64(domain.Cardinality).Inverse(&domain.CardinalityInv)
+
+	domain.twiddles = make([]fr.Element, domain.Cardinality)
+	domain.twiddlesInv = make([]fr.Element, domain.Cardinality)
+	domain.twiddles[0].SetOne()
+	domain.twiddlesInv[0].SetOne()
+	if domain.Cardinality > 1 {
+		precomputeExpTable(domain.Generator, domain.twiddles)
+		precomputeExpTable(domain.GeneratorInv, domain.twiddlesInv)
+	}
+
+	// Generator**(3**b) generates the subgroup of size 2**a
+	r := pow3(log3)
+	domain.radix2 = &Domain{Cardinality: uint64(1) << log2}
+	domain.radix2.Generator = domain.twiddles[r%domain.Cardinality]
+	domain.radix2.GeneratorInv = domain.twiddlesInv[r%domain.Cardinality]
+	domain.radix2.preComputeTwiddles()
+
+	return domain, nil
+}
+
+// FFT computes the discrete Fourier transform of a and stores the result in a: a[i] is replaced by the
+// evaluation at Generator**i of the polynomial of coefficients a. len(a) must be the cardinality of the
+// domain, the input and the output are in natural order.
+func (domain *MixedRadixDomain) FFT(a []fr.Element) {
+	domain.fft(a, domain.twiddles, domain.radix2.Twiddles)
+}
+
+// FFTInverse computes the inverse discrete Fourier transform of a and stores the result in a: a is
+// replaced by the coefficients of the polynomial whose evaluations at Generator**i are a[i].
+// len(a) must be the cardinality of the domain, the input and the output are in natural order.
+func (domain *MixedRadixDomain) FFTInverse(a []fr.Element) {
+	domain.fft(a, domain.twiddlesInv, domain.radix2.TwiddlesInv)
+	parallel.Execute(len(a), func(start, end int) {
+		for i := start; i < end; i++ {
+			a[i].Mul(&a[i], &domain.CardinalityInv)
+		}
+	})
+}
+
+// fft computes the transform of size n = R*M, R = 3**b and M = 2**a, with the decomposition of Cooley and Tukey:
+// the M-point transforms Y_q of the R decimated sequences a[q + R*j] are computed with the radix 2 FFT, and
+// a[k + M*p] = Sum_q (w**(q*k) * Y_q[k]) * w**(M*p*q) is an R-point transform, computed with radix 3 butterflies.
+func (domain *MixedRadixDomain) fft(a, twiddles []fr.Element, radix2Twiddles [][]fr.Element) {
+	if uint64(len(a)) != domain.Cardinality {
+		panic("the size of a must be the cardinality of the domain")
+	}
+	n := len(a)
+	r := int(pow3(domain.Log3))
+	m := n / r
+
+	// find the stage where we should stop spawning go routines in our recursive calls
+	// (ie when we have as many go routines running as we have available CPUs)
+	numCPU := uint64(runtime.NumCPU())
+	maxSplits := bits.TrailingZeros64(nextPowerOfTwo(numCPU))
+	if numCPU <= 1 {
+		maxSplits = -1
+	}
+
+	if r == 1 {
+		difFFT(a, radix2Twiddles, 0, maxSplits, nil)
+		BitReverse(a)
+		return
+	}
+
+	// radix 2 transforms of the decimated sequences, gathered in bit-reversed order
+	y := make([]fr.Element, n)
+	nn := uint64(64 - bits.TrailingZeros64(uint64(m)))
+	parallel.Execute(m, func(start, end int) {
+		for j := start; j < end; j++ {
+			jrev := int(bits.Reverse64(uint64(j)) >> nn)
+			for q := 0; q < r; q++ {
+				y[q*m+jrev] = a[q+r*j]
+			}
+		}
+	})
+	for q := 0; q < r; q++ {
+		ditFFT(y[q*m:(q+1)*m], radix2Twiddles, 0, maxSplits, nil)
+	}
+
+	// radix 3 transforms, twiddles[n/r] being a primitive r-th root of unity
+	parallel.Execute(m, func(start, end int) {
+		t := make([]fr.Element, r)
+		x := make([]fr.Element, r)
+		for k := start; k < end; k++ {
+			t[0] = y[k]
+			for q := 1; q < r; q++ {
+				t[q].Mul(&y[q*m+k], &twiddles[q*k])
+			}
+			radix3FFT(x, t, 1, twiddles, n/r)
+			for p := 0; p < r; p++ {
+				a[k+m*p] = x[p]
+			}
+		}
+	})
+}
+
+// radix3FFT computes out[k] = Sum_j in[j*stride]*twiddles[j*k*step] for k < len(out), len(out) being a power of 3
+// and twiddles[step] a primitive len(out)-th root of unity (decimation in time)
+func radix3FFT(out, in []fr.Element, stride int, twiddles []fr.Element, step int) {
+	n := len(out)
+	if n == 1 {
+		out[0] = in[0]
+		return
+	}
+	m := n / 3
+	for q := 0; q < 3; q++ {
+		radix3FFT(out[q*m:(q+1)*m], in[q*stride:], stride*3, twiddles, step*3)
+	}
+
+	// with w a primitive 3rd root of unity, w**2 = -1 - w and the butterfly is
+	// (x0 + t1 + t2, x0 - t2 + w*(t1 - t2), x0 - t1 - w*(t1 - t2))
+	w := twiddles[m*step]
+	var t1, t2, u fr.Element
+	for k := 0; k < m; k++ {
+		t1.Mul(&out[k+m], &twiddles[k*step])
+		t2.Mul(&out[k+2*m], &twiddles[2*k*step])
+		u.Sub(&t1, &t2).Mul(&u, &w)
+		out[k+m].Sub(&out[k], &t2).Add(&out[k+m], &u)
+		out[k+2*m].Sub(&out[k], &t1).Sub(&out[k+2*m], &u)
+		out[k].Add(&out[k], &t1).Add(&out[k], &t2)
+	}
+}
+
+// mixedRadixSize returns a and b such that 2**a * 3**b is the smallest integer >= m of this form,
+// with a <= maxOrder2 and b <= maxOrder3, and false if there are none
+func mixedRadixSize(m, maxOrder2, maxOrder3 uint64) (uint64, uint64, bool) {
+	var log2, log3, size uint64
+	found := false
+	for b := uint64(0); b <= maxOrder3; b++ {
+		p3 := pow3(b)
+
+		// smallest a such that 2**a * 3**b >= m
+		a := uint64(0)
+		if m > p3 {
+			q := (m + p3 - 1) / p3
+			a = uint64(bits.Len64(q - 1))
+		}
+		if a > maxOrder2 {
+			continue
+		}
+		if s := (uint64(1) << a) * p3; !found || s < size {
+			log2, log3, size, found = a, b, s, true
+		}
+	}
+	return log2, log3, found
+}
+
+// threeAdicRootOfUnity returns the generator of the largest 3-adic subgroup of fr, and the log3 of its order
+func threeAdicRootOfUnity() (fr.Element, uint64) {
+	var rootOfUnity fr.Element
+
+	rootOfUnity.SetString("8444461749428370424248824938781546531284005582649182570233710176290576793600")
+	const maxOrderRoot uint64 = 1
+
+	return rootOfUnity, maxOrderRoot
+}
+
+func pow3(n uint64) uint64 {
+	res := uint64(1)
+	for i := uint64(0); i < n; i++ {
+		res *= 3
+	}
+	return res
+}
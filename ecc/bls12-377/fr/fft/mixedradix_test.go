@@ -0,0 +1,134 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fft
+
+import (
+	"math/big"
+	"strconv"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func TestMixedRadixSize(t *testing.T) {
+
+	for _, test := range []struct {
+		m, maxOrder2, maxOrder3 uint64
+		log2, log3              uint64
+		ok                      bool
+	}{
+		{0, 28, 2, 0, 0, true},
+		{1, 28, 2, 0, 0, true},
+		{5, 28, 2, 1, 1, true},
+		{7, 28, 2, 3, 0, true},
+		{17, 28, 2, 1, 2, true},
+		{17, 28, 1, 3, 1, true},
+		{100, 28, 0, 7, 0, true},
+		{1 << 28, 28, 2, 28, 0, true},
+		{1<<28 + 1, 28, 2, 25, 2, true},
+		{1<<28*9 + 1, 28, 2, 0, 0, false},
+	} {
+		log2, log3, ok := mixedRadixSize(test.m, test.maxOrder2, test.maxOrder3)
+		if ok != test.ok || (ok && (log2 != test.log2 || log3 != test.log3)) {
+			t.Fatalf("wrong size for %d: got 2**%d * 3**%d", test.m, log2, log3)
+		}
+	}
+
+	_, maxOrder2 := twoAdicRootOfUnity()
+	_, maxOrder3 := threeAdicRootOfUnity()
+	if _, err := NewMixedRadixDomain((uint64(1)<<maxOrder2)*pow3(maxOrder3) + 1); err != ErrUnsupportedSize {
+		t.Fatal("the size should not be supported")
+	}
+}
+
+func TestMixedRadixFFT(t *testing.T) {
+
+	for _, m := range []uint64{1, 2, 3, 6, 12, 48, 144, 200} {
+		domain, err := NewMixedRadixDomain(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := domain.Cardinality
+		if n < m || n != (uint64(1)<<domain.Log2)*pow3(domain.Log3) {
+			t.Fatalf("wrong cardinality %d for %d", n, m)
+		}
+
+		// the generator has order n
+		one := fr.One()
+		var x fr.Element
+		x.Exp(domain.Generator, new(big.Int).SetUint64(n))
+		if !x.Equal(&one) {
+			t.Fatal("the order of the generator should divide the cardinality")
+		}
+		for _, p := range []uint64{2, 3} {
+			if n%p == 0 {
+				x.Exp(domain.Generator, new(big.Int).SetUint64(n/p))
+				if x.Equal(&one) {
+					t.Fatal("the generator should have the order of the cardinality")
+				}
+			}
+		}
+
+		pol := make([]fr.Element, n)
+		for i := uint64(0); i < n; i++ {
+			pol[i].SetRandom()
+		}
+		backupPol := make([]fr.Element, n)
+		copy(backupPol, pol)
+
+		domain.FFT(pol)
+		x.SetOne()
+		for i := uint64(0); i < n; i++ {
+			eval := evaluatePolynomial(backupPol, x)
+			if !eval.Equal(&pol[i]) {
+				t.Fatalf("wrong evaluation for cardinality %d", n)
+			}
+			x.Mul(&x, &domain.Generator)
+		}
+
+		domain.FFTInverse(pol)
+		for i := uint64(0); i < n; i++ {
+			if !pol[i].Equal(&backupPol[i]) {
+				t.Fatalf("FFTInverse(FFT) should be the identity for cardinality %d", n)
+			}
+		}
+	}
+}
+
+func BenchmarkMixedRadixFFT(b *testing.B) {
+
+	const maxSize = 3 << 19
+
+	pol := make([]fr.Element, maxSize)
+	for i := uint64(0); i < maxSize; i++ {
+		pol[i].SetRandom()
+	}
+
+	for i := 8; i < 20; i++ {
+		sizeDomain := 3 << i
+		_pol := make([]fr.Element, sizeDomain)
+		b.Run("fft 3*2**"+strconv.Itoa(i), func(b *testing.B) {
+			copy(_pol, pol)
+			domain, _ := NewMixedRadixDomain(uint64(sizeDomain))
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				domain.FFT(_pol)
+			}
+		})
+	}
+
+}
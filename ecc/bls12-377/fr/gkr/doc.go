@@ -0,0 +1,29 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package gkr implements the GKR protocol of Goldwasser, Kalai and Rothblum
+// over the scalar field of bls12-377, made non interactive with Fiat Shamir.
+//
+// A layered arithmetic circuit is evaluated in parallel on a batch of 2**n instances, and each
+// wire is seen as a multilinear polynomial in n variables, whose evaluations on the boolean
+// hypercube are its values on the instances. Starting from a random evaluation of the outputs,
+// the claims on the wires of each layer are reduced with a sumcheck to claims on the wires of
+// the previous layer, until the inputs, which the verifier evaluates by itself.
+//
+// The gates are polynomials of low degree in their inputs: besides the addition and the
+// multiplication, custom gates such as the rounds of MiMC can be used, so that a batch of hashes
+// is proven at a cost linear in the size of the batch.
+package gkr
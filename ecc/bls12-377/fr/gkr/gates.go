@@ -0,0 +1,60 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gkr
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// AddGate returns the sum of its inputs
+type AddGate struct{}
+
+func (AddGate) Evaluate(inputs ...fr.Element) fr.Element {
+	res := inputs[0]
+	for j := 1; j < len(inputs); j++ {
+		res.Add(&res, &inputs[j])
+	}
+	return res
+}
+
+func (AddGate) Degree() int {
+	return 1
+}
+
+// MulGate returns the product of its two inputs
+type MulGate struct{}
+
+func (MulGate) Evaluate(inputs ...fr.Element) fr.Element {
+	var res fr.Element
+	res.Mul(&inputs[0], &inputs[1])
+	return res
+}
+
+func (MulGate) Degree() int {
+	return 2
+}
+
+// IdentityGate returns its input, it carries a value to the next layer
+type IdentityGate struct{}
+
+func (IdentityGate) Evaluate(inputs ...fr.Element) fr.Element {
+	return inputs[0]
+}
+
+func (IdentityGate) Degree() int {
+	return 1
+}
@@ -0,0 +1,494 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gkr
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/sumcheck"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidCircuit    = errors.New("invalid circuit: the wires of the input layer have no gate, the other ones have a gate whose inputs are in the previous layer")
+	ErrInvalidAssignment = errors.New("the assignment does not match the circuit, or its number of instances is not a power of 2 greater than 1")
+	ErrInvalidProof      = errors.New("can't verify gkr proof")
+)
+
+// Gate polynomial of low degree, computing the value of a wire from the values of its inputs
+type Gate interface {
+
+	// Evaluate returns the output of the gate
+	Evaluate(inputs ...fr.Element) fr.Element
+
+	// Degree returns the total degree of the gate
+	Degree() int
+}
+
+// Wire of a Layer. On each instance, its value is the output of Gate applied to the values of the
+// wires Inputs of the previous layer. The wires of the input layer have no Gate and no Inputs.
+type Wire struct {
+	Gate   Gate
+	Inputs []int
+}
+
+// Layer of a Circuit
+type Layer []Wire
+
+// Circuit layered arithmetic circuit, evaluated in parallel on a batch of 2**n instances.
+// Circuit[0] is the input layer, the last layer is the output layer.
+type Circuit []Layer
+
+// Assignment values of the wires of a Circuit on all the instances, Assignment[i][j] being the values
+// of the j-th wire of the i-th layer, seen as a multilinear polynomial in n variables.
+type Assignment [][]polynomial.MultiLin
+
+// WireProof reduction of the claims on a wire to claims on its inputs
+type WireProof struct {
+
+	// Sumcheck proof of Sum_b Sum_k lambda**k*eq(z_k, b)*Gate(inputs(b)) = Sum_k lambda**k*v_k,
+	// (z_k, v_k) being the claims on the wire
+	Sumcheck sumcheck.Proof
+
+	// InputEvaluations values of the inputs of the wire at the challenges r of the sumcheck,
+	// which become the claims on the wires of the previous layer
+	InputEvaluations []fr.Element
+}
+
+// Proof of the evaluation of a Circuit. Proof[i][j] is the WireProof of the j-th wire of the i-th
+// layer, Proof[0] is empty, and so are the proofs of the wires which are not used.
+type Proof [][]WireProof
+
+// Assign evaluates c on a batch of instances, inputs[j] being the values of the j-th input
+// on all the instances
+func (c Circuit) Assign(inputs []polynomial.MultiLin) (Assignment, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	if len(inputs) != len(c[0]) {
+		return nil, ErrInvalidAssignment
+	}
+	if _, err := nbVariables(inputs); err != nil {
+		return nil, err
+	}
+	nbInstances := len(inputs[0])
+
+	res := make(Assignment, len(c))
+	res[0] = inputs
+	for l := 1; l < len(c); l++ {
+		res[l] = make([]polynomial.MultiLin, len(c[l]))
+		for w, wire := range c[l] {
+			res[l][w] = make(polynomial.MultiLin, nbInstances)
+			values := res[l][w]
+			previous := res[l-1]
+			parallel.Execute(nbInstances, func(start, end int) {
+				in := make([]fr.Element, len(wire.Inputs))
+				for i := start; i < end; i++ {
+					for j, k := range wire.Inputs {
+						in[j] = previous[k][i]
+					}
+					values[i] = wire.Gate.Evaluate(in...)
+				}
+			})
+		}
+	}
+
+	return res, nil
+}
+
+// Outputs returns the values of the output wires
+func (a Assignment) Outputs() []polynomial.MultiLin {
+	return a[len(a)-1]
+}
+
+// ChallengeNames returns the names of the challenges of a proof of the evaluation of c on 2**nbVars
+// instances. They must be declared in this order in the fiatshamir.Transcript given to Prove and Verify.
+func ChallengeNames(c Circuit, nbVars int) []string {
+	res := make([]string, 0, nbVars)
+	for i := 0; i < nbVars; i++ {
+		res = append(res, outputChallengeName(i))
+	}
+
+	nbClaims := c.nbClaims()
+	for l := len(c) - 1; l > 0; l-- {
+		for w := 0; w < len(c[l]); w++ {
+			if nbClaims[l][w] == 0 {
+				continue
+			}
+			res = append(res, wirePrefix(l, w)+"lambda")
+			res = append(res, sumcheck.ChallengeNames(wirePrefix(l, w), nbVars)...)
+		}
+	}
+
+	return res
+}
+
+// Prove returns a proof that assignment is the evaluation of c on its inputs.
+// The challenges are derived from transcript with the names ChallengeNames(c, n).
+func Prove(transcript *fiatshamir.Transcript, c Circuit, assignment Assignment) (Proof, error) {
+	if err := c.check(); err != nil {
+		return nil, err
+	}
+	if len(assignment) != len(c) {
+		return nil, ErrInvalidAssignment
+	}
+	for l := 0; l < len(c); l++ {
+		if len(assignment[l]) != len(c[l]) {
+			return nil, ErrInvalidAssignment
+		}
+	}
+	n, err := nbVariables(assignment...)
+	if err != nil {
+		return nil, err
+	}
+
+	// the outputs are evaluated at a random point z
+	z, err := deriveOutputChallenges(transcript, n, assignment[0], assignment.Outputs())
+	if err != nil {
+		return nil, err
+	}
+	claims := newClaims(c)
+	last := len(c) - 1
+	for w := 0; w < len(c[last]); w++ {
+		v, err := assignment[last][w].Evaluate(z)
+		if err != nil {
+			return nil, err
+		}
+		claims[last][w].add(z, v)
+	}
+
+	proof := make(Proof, len(c))
+	for l := last; l > 0; l-- {
+		proof[l] = make([]WireProof, len(c[l]))
+		for w, wire := range c[l] {
+			if len(claims[l][w].values) == 0 {
+				continue
+			}
+			lambda, err := claims[l][w].deriveLambda(transcript, wirePrefix(l, w)+"lambda")
+			if err != nil {
+				return nil, err
+			}
+
+			// Sum_b eq(b)*Gate(inputs(b)) with eq = Sum_k lambda**k*eq(z_k, X), the sumcheck
+			// folds the multilinear polynomials in place
+			m := make([]polynomial.MultiLin, 1+len(wire.Inputs))
+			m[0] = claims[l][w].eqTable(lambda)
+			for j, k := range wire.Inputs {
+				m[j+1] = assignment[l-1][k].Clone()
+			}
+			sumcheckProof, r, evaluations, err := sumcheck.ProveFunction(transcript, wirePrefix(l, w), wireFunction{wire.Gate}, m)
+			if err != nil {
+				return nil, err
+			}
+
+			proof[l][w].Sumcheck = sumcheckProof
+			proof[l][w].InputEvaluations = evaluations[1:]
+			for j, k := range wire.Inputs {
+				claims[l-1][k].add(r, evaluations[j+1])
+			}
+		}
+	}
+
+	return proof, nil
+}
+
+// Verify checks that outputs is the evaluation of c on inputs
+func Verify(transcript *fiatshamir.Transcript, c Circuit, inputs, outputs []polynomial.MultiLin, proof Proof) error {
+	if err := c.check(); err != nil {
+		return err
+	}
+	if len(inputs) != len(c[0]) || len(outputs) != len(c[len(c)-1]) {
+		return ErrInvalidAssignment
+	}
+	n, err := nbVariables(inputs, outputs)
+	if err != nil {
+		return err
+	}
+	if len(proof) != len(c) {
+		return ErrInvalidProof
+	}
+
+	z, err := deriveOutputChallenges(transcript, n, inputs, outputs)
+	if err != nil {
+		return err
+	}
+	claims := newClaims(c)
+	last := len(c) - 1
+	for w := 0; w < len(c[last]); w++ {
+		v, err := outputs[w].Evaluate(z)
+		if err != nil {
+			return err
+		}
+		claims[last][w].add(z, v)
+	}
+
+	for l := last; l > 0; l-- {
+		if len(proof[l]) != len(c[l]) {
+			return ErrInvalidProof
+		}
+		for w, wire := range c[l] {
+			if len(claims[l][w].values) == 0 {
+				continue
+			}
+			wireProof := &proof[l][w]
+			if len(wireProof.InputEvaluations) != len(wire.Inputs) {
+				return ErrInvalidProof
+			}
+			lambda, err := claims[l][w].deriveLambda(transcript, wirePrefix(l, w)+"lambda")
+			if err != nil {
+				return err
+			}
+
+			r, finalClaim, err := sumcheck.Verify(transcript, wirePrefix(l, w), claims[l][w].combine(lambda), n, wire.Gate.Degree()+1, &wireProof.Sumcheck)
+			if err == sumcheck.ErrInvalidProof {
+				return ErrInvalidProof
+			}
+			if err != nil {
+				return err
+			}
+
+			// the final claim is eq(r)*Gate(inputs(r))
+			eq, err := claims[l][w].evaluateEq(lambda, r)
+			if err != nil {
+				return err
+			}
+			expected := wire.Gate.Evaluate(wireProof.InputEvaluations...)
+			expected.Mul(&expected, &eq)
+			if !expected.Equal(&finalClaim) {
+				return ErrInvalidProof
+			}
+
+			for j, k := range wire.Inputs {
+				claims[l-1][k].add(r, wireProof.InputEvaluations[j])
+			}
+		}
+	}
+
+	// the claims on the inputs are checked directly
+	for w := 0; w < len(c[0]); w++ {
+		for k := 0; k < len(claims[0][w].values); k++ {
+			v, err := inputs[w].Evaluate(claims[0][w].points[k])
+			if err != nil {
+				return err
+			}
+			if !v.Equal(&claims[0][w].values[k]) {
+				return ErrInvalidProof
+			}
+		}
+	}
+
+	return nil
+}
+
+// check returns an error if c is not a valid layered circuit
+func (c Circuit) check() error {
+	if len(c) < 2 || len(c[0]) == 0 {
+		return ErrInvalidCircuit
+	}
+	for _, wire := range c[0] {
+		if wire.Gate != nil || len(wire.Inputs) != 0 {
+			return ErrInvalidCircuit
+		}
+	}
+	for l := 1; l < len(c); l++ {
+		if len(c[l]) == 0 {
+			return ErrInvalidCircuit
+		}
+		for _, wire := range c[l] {
+			if wire.Gate == nil || len(wire.Inputs) == 0 {
+				return ErrInvalidCircuit
+			}
+			for _, k := range wire.Inputs {
+				if k < 0 || k >= len(c[l-1]) {
+					return ErrInvalidCircuit
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// nbVariables returns the number of variables of multilinear polynomials, which must have the same
+// number of evaluations, a power of 2 greater than 1
+func nbVariables(layers ...[]polynomial.MultiLin) (int, error) {
+	nbInstances := len(layers[0][0])
+	if nbInstances < 2 || nbInstances&(nbInstances-1) != 0 {
+		return 0, ErrInvalidAssignment
+	}
+	for _, layer := range layers {
+		for _, m := range layer {
+			if len(m) != nbInstances {
+				return 0, ErrInvalidAssignment
+			}
+		}
+	}
+	return layers[0][0].NbVars(), nil
+}
+
+// nbClaims returns the number of claims on each wire during the verification: one for each output,
+// and one for each use of the wire as the input of a gate
+func (c Circuit) nbClaims() [][]int {
+	res := make([][]int, len(c))
+	for l := 0; l < len(c); l++ {
+		res[l] = make([]int, len(c[l]))
+	}
+	for w := 0; w < len(c[len(c)-1]); w++ {
+		res[len(c)-1][w] = 1
+	}
+	for l := len(c) - 1; l > 0; l-- {
+		for w, wire := range c[l] {
+			if res[l][w] == 0 {
+				continue
+			}
+			for _, k := range wire.Inputs {
+				res[l-1][k]++
+			}
+		}
+	}
+	return res
+}
+
+// claims on the values of a wire: its multilinear polynomial evaluates to values[k] at points[k]
+type claims struct {
+	points [][]fr.Element
+	values []fr.Element
+}
+
+func newClaims(c Circuit) [][]claims {
+	res := make([][]claims, len(c))
+	for l := 0; l < len(c); l++ {
+		res[l] = make([]claims, len(c[l]))
+	}
+	return res
+}
+
+func (c *claims) add(point []fr.Element, value fr.Element) {
+	c.points = append(c.points, point)
+	c.values = append(c.values, value)
+}
+
+// deriveLambda derives the challenge used to combine the claims, binded to their values
+func (c *claims) deriveLambda(transcript *fiatshamir.Transcript, name string) (fr.Element, error) {
+	for k := 0; k < len(c.values); k++ {
+		b := c.values[k].Bytes()
+		if err := transcript.Bind(name, b[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	b, err := transcript.ComputeChallenge(name)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(b)
+	return res, nil
+}
+
+// combine returns Sum_k lambda**k*values[k]
+func (c *claims) combine(lambda fr.Element) fr.Element {
+	var res fr.Element
+	for k := len(c.values) - 1; k >= 0; k-- {
+		res.Mul(&res, &lambda).Add(&res, &c.values[k])
+	}
+	return res
+}
+
+// eqTable returns the multilinear polynomial Sum_k lambda**k*eq(points[k], X)
+func (c *claims) eqTable(lambda fr.Element) polynomial.MultiLin {
+	res := polynomial.EqTable(c.points[0])
+	var lambdaK fr.Element
+	lambdaK.SetOne()
+	for k := 1; k < len(c.points); k++ {
+		lambdaK.Mul(&lambdaK, &lambda)
+		eq := polynomial.EqTable(c.points[k])
+		scale := lambdaK
+		parallel.Execute(len(res), func(start, end int) {
+			var t fr.Element
+			for i := start; i < end; i++ {
+				t.Mul(&eq[i], &scale)
+				res[i].Add(&res[i], &t)
+			}
+		})
+	}
+	return res
+}
+
+// evaluateEq returns Sum_k lambda**k*eq(points[k], r)
+func (c *claims) evaluateEq(lambda fr.Element, r []fr.Element) (fr.Element, error) {
+	var res fr.Element
+	for k := len(c.points) - 1; k >= 0; k-- {
+		eq, err := polynomial.EvaluateEq(c.points[k], r)
+		if err != nil {
+			return fr.Element{}, err
+		}
+		res.Mul(&res, &lambda).Add(&res, &eq)
+	}
+	return res, nil
+}
+
+// wireFunction f(e, inputs...) = e*Gate(inputs...), whose sum is proven by the sumcheck of a wire
+type wireFunction struct {
+	gate Gate
+}
+
+func (f wireFunction) Evaluate(inputs ...fr.Element) fr.Element {
+	res := f.gate.Evaluate(inputs[1:]...)
+	res.Mul(&res, &inputs[0])
+	return res
+}
+
+func (f wireFunction) Degree() int {
+	return f.gate.Degree() + 1
+}
+
+// deriveOutputChallenges derives the point z at which the outputs are evaluated,
+// binded to the inputs and the outputs
+func deriveOutputChallenges(transcript *fiatshamir.Transcript, nbVars int, inputs, outputs []polynomial.MultiLin) ([]fr.Element, error) {
+	first := outputChallengeName(0)
+	for _, values := range [][]polynomial.MultiLin{inputs, outputs} {
+		for _, m := range values {
+			for i := 0; i < len(m); i++ {
+				b := m[i].Bytes()
+				if err := transcript.Bind(first, b[:]); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	res := make([]fr.Element, nbVars)
+	for i := 0; i < nbVars; i++ {
+		b, err := transcript.ComputeChallenge(outputChallengeName(i))
+		if err != nil {
+			return nil, err
+		}
+		res[i].SetBytes(b)
+	}
+	return res, nil
+}
+
+func outputChallengeName(i int) string {
+	return "gkr.z." + strconv.Itoa(i)
+}
+
+func wirePrefix(layer, wire int) string {
+	return "gkr." + strconv.Itoa(layer) + "." + strconv.Itoa(wire) + "."
+}
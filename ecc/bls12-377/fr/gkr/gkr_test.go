@@ -0,0 +1,160 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package gkr
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+func randomMultiLin(nbVars int) polynomial.MultiLin {
+	m := make(polynomial.MultiLin, 1<<nbVars)
+	for i := 0; i < len(m); i++ {
+		m[i].SetRandom()
+	}
+	return m
+}
+
+// testCircuit on the inputs (a, b, c) computes (a*b)*(b + c) and b + 2*c,
+// the wire a*b + b of the second layer is not used
+func testCircuit() Circuit {
+	return Circuit{
+		{{}, {}, {}},
+		{
+			{Gate: MulGate{}, Inputs: []int{0, 1}},
+			{Gate: AddGate{}, Inputs: []int{1, 2}},
+			{Gate: IdentityGate{}, Inputs: []int{2}},
+			{Gate: AddGate{}, Inputs: []int{0, 1}},
+		},
+		{
+			{Gate: MulGate{}, Inputs: []int{0, 1}},
+			{Gate: AddGate{}, Inputs: []int{1, 2}},
+		},
+	}
+}
+
+func newTranscript(c Circuit, nbVars int) *fiatshamir.Transcript {
+	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(c, nbVars)...)
+	return &fs
+}
+
+func TestAssign(t *testing.T) {
+
+	const nbVars = 3
+	c := testCircuit()
+	inputs := []polynomial.MultiLin{randomMultiLin(nbVars), randomMultiLin(nbVars), randomMultiLin(nbVars)}
+
+	assignment, err := c.Assign(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := assignment.Outputs()
+	for i := 0; i < 1<<nbVars; i++ {
+		var expected, t0 fr.Element
+		expected.Mul(&inputs[0][i], &inputs[1][i])
+		t0.Add(&inputs[1][i], &inputs[2][i])
+		expected.Mul(&expected, &t0)
+		if !expected.Equal(&outputs[0][i]) {
+			t.Fatal("wrong value of the first output")
+		}
+		expected.Double(&inputs[2][i]).Add(&expected, &inputs[1][i])
+		if !expected.Equal(&outputs[1][i]) {
+			t.Fatal("wrong value of the second output")
+		}
+	}
+
+	if _, err := c.Assign(inputs[:2]); err != ErrInvalidAssignment {
+		t.Fatal("an assignment with missing inputs should be rejected")
+	}
+	inputs[1] = randomMultiLin(nbVars + 1)
+	if _, err := c.Assign(inputs); err != ErrInvalidAssignment {
+		t.Fatal("inputs with different numbers of instances should be rejected")
+	}
+
+	c[1][0].Inputs = []int{0, 3}
+	if _, err := c.Assign(inputs); err != ErrInvalidCircuit {
+		t.Fatal("a gate with an input out of the previous layer should be rejected")
+	}
+}
+
+func TestGKR(t *testing.T) {
+
+	c := testCircuit()
+	for _, nbVars := range []int{1, 4} {
+		inputs := []polynomial.MultiLin{randomMultiLin(nbVars), randomMultiLin(nbVars), randomMultiLin(nbVars)}
+		assignment, err := c.Assign(inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		proof, err := Prove(newTranscript(c, nbVars), c, assignment)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Verify(newTranscript(c, nbVars), c, inputs, assignment.Outputs(), proof); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGKRInvalidProof(t *testing.T) {
+
+	const nbVars = 3
+	c := testCircuit()
+	inputs := []polynomial.MultiLin{randomMultiLin(nbVars), randomMultiLin(nbVars), randomMultiLin(nbVars)}
+	assignment, err := c.Assign(inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := Prove(newTranscript(c, nbVars), c, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// wrong output
+	outputs := []polynomial.MultiLin{assignment.Outputs()[0].Clone(), assignment.Outputs()[1]}
+	outputs[0][5].Double(&outputs[0][5])
+	if err := Verify(newTranscript(c, nbVars), c, inputs, outputs, proof); err != ErrInvalidProof {
+		t.Fatal("verifying a proof against wrong outputs should have failed")
+	}
+
+	// wrong input
+	_inputs := []polynomial.MultiLin{inputs[0], inputs[1], inputs[2].Clone()}
+	_inputs[2][0].Double(&_inputs[2][0])
+	if err := Verify(newTranscript(c, nbVars), c, _inputs, assignment.Outputs(), proof); err != ErrInvalidProof {
+		t.Fatal("verifying a proof against wrong inputs should have failed")
+	}
+
+	// proof of a wrong assignment
+	assignment[1][1][2].Double(&assignment[1][1][2])
+	wrongProof, err := Prove(newTranscript(c, nbVars), c, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(newTranscript(c, nbVars), c, inputs, assignment.Outputs(), wrongProof); err != ErrInvalidProof {
+		t.Fatal("verifying a proof of a wrong assignment should have failed")
+	}
+
+	// tampered input evaluation
+	proof[1][0].InputEvaluations[1].Double(&proof[1][0].InputEvaluations[1])
+	if err := Verify(newTranscript(c, nbVars), c, inputs, assignment.Outputs(), proof); err != ErrInvalidProof {
+		t.Fatal("verifying a tampered proof should have failed")
+	}
+}
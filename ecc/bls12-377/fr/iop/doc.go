@@ -0,0 +1,31 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package iop provides the building blocks of the permutation and lookup arguments of
+// PLONK-like provers over the scalar field of bls12-377.
+//
+// The columns are vectors of size n, seen as polynomials in Lagrange form on a fft.Domain of
+// size n, their i-th value being the evaluation at w**i (natural order). For each argument:
+//   - the prover builds the accumulator polynomials from the columns and the challenges,
+//   - the contribution of the argument to the quotient t = (Sum_k alpha**k*identity_k)/Z_H is
+//     evaluated on a coset of a larger domain, where the contributions of all the arguments of a
+//     proof system are added before going back to the canonical basis with CosetToCanonical,
+//   - the verifier evaluates the same contribution at a point zeta from the openings of the
+//     committed polynomials, and checks that the sum of the contributions is t(zeta).
+//
+// The challenges are parameters: the caller derives them with a fiatshamir.Transcript bound to
+// its commitments.
+package iop
@@ -0,0 +1,162 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package iop
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidSize           = errors.New("the columns must have the size of the domain")
+	ErrInvalidQuotientDomain = errors.New("the domain of the quotient is too small, or has no coset")
+	ErrNotSatisfied          = errors.New("the columns don't satisfy the argument")
+)
+
+// CosetToCanonical returns the polynomial whose evaluations on the coset used for the quotient
+// contributions, g*<W> with g = bigDomain.FinerGenerator and W = bigDomain.Generator, are evaluations,
+// in natural order. It is computed with an inverse FFT.
+func CosetToCanonical(bigDomain *fft.Domain, evaluations []fr.Element) polynomial.Polynomial {
+	res := make(polynomial.Polynomial, len(evaluations))
+	copy(res, evaluations)
+	fft.BitReverse(res)
+	bigDomain.FFTInverse(res, fft.DIT, 1)
+	return res
+}
+
+// coset precomputed data to evaluate the identities of an argument on the coset g*<W> of the domain
+// of the quotient, of size N, the columns being defined on the domain <w> of size n
+type coset struct {
+	domain, bigDomain *fft.Domain
+
+	// ratio N/n: w = W**ratio, and the evaluation of p(w*X) at the i-th point of the coset
+	// is the one of p at the (i + ratio)-th point
+	ratio int
+
+	// points g*W**i of the coset
+	points []fr.Element
+
+	// vanishing, vanishingInv Z_H(x) = x**n - 1 and its inverse on the coset, the i-th point
+	// having the value of index i mod ratio
+	vanishing, vanishingInv []fr.Element
+}
+
+// newCoset returns the coset of bigDomain on which are evaluated the identities of degree
+// at most (factor+1)*(n-1)+1, whose quotients by Z_H have a degree smaller than factor*n
+func newCoset(domain, bigDomain *fft.Domain, factor uint64) (*coset, error) {
+	if bigDomain.Depth == 0 || bigDomain.Cardinality < factor*domain.Cardinality {
+		return nil, ErrInvalidQuotientDomain
+	}
+	res := coset{
+		domain:    domain,
+		bigDomain: bigDomain,
+		ratio:     int(bigDomain.Cardinality / domain.Cardinality),
+		points:    make([]fr.Element, bigDomain.Cardinality),
+	}
+
+	res.points[0] = bigDomain.FinerGenerator
+	for i := 1; i < len(res.points); i++ {
+		res.points[i].Mul(&res.points[i-1], &bigDomain.Generator)
+	}
+
+	// Z_H doesn't vanish on the coset since g**n is not in <W**n>
+	res.vanishing = make([]fr.Element, res.ratio)
+	for i := 0; i < res.ratio; i++ {
+		res.vanishing[i] = polynomial.EvaluateVanishing(domain, res.points[i])
+	}
+	res.vanishingInv = polynomial.BatchInvertOrZero(res.vanishing)
+
+	return &res, nil
+}
+
+// evaluate returns the evaluations of p, of size at most n, on the coset in natural order
+func (c *coset) evaluate(p polynomial.Polynomial) ([]fr.Element, error) {
+	if uint64(len(p)) > c.domain.Cardinality {
+		return nil, ErrInvalidSize
+	}
+	res := make([]fr.Element, c.bigDomain.Cardinality)
+	copy(res, p)
+	c.bigDomain.FFT(res, fft.DIF, 1)
+	fft.BitReverse(res)
+	return res, nil
+}
+
+// next returns the index of the evaluation of p(w*X) at the i-th point of the coset
+func (c *coset) next(i int) int {
+	return (i + c.ratio) % len(c.points)
+}
+
+// lagrange returns the evaluations on the coset of the j-th Lagrange polynomial of the domain,
+// L_j(x) = w**j*(x**n - 1)/(n*(x - w**j))
+func (c *coset) lagrange(j uint64) []fr.Element {
+	var wj fr.Element
+	wj.Exp(c.domain.Generator, new(big.Int).SetUint64(j))
+
+	res := make([]fr.Element, len(c.points))
+	for i := 0; i < len(res); i++ {
+		res[i].Sub(&c.points[i], &wj)
+	}
+	res = polynomial.BatchInvertOrZero(res)
+
+	var s fr.Element
+	s.Mul(&wj, &c.domain.CardinalityInv)
+	parallel.Execute(len(res), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i].Mul(&res[i], &c.vanishing[i%c.ratio]).Mul(&res[i], &s)
+		}
+	})
+	return res
+}
+
+// divideByVanishing divides in place the evaluations on the coset by Z_H
+func (c *coset) divideByVanishing(evaluations []fr.Element) {
+	parallel.Execute(len(evaluations), func(start, end int) {
+		for i := start; i < end; i++ {
+			evaluations[i].Mul(&evaluations[i], &c.vanishingInv[i%c.ratio])
+		}
+	})
+}
+
+// evaluateLagrange returns L_j(zeta) = w**j*(zeta**n - 1)/(n*(zeta - w**j)), L_j being the j-th
+// Lagrange polynomial of the domain
+func evaluateLagrange(domain *fft.Domain, j uint64, zeta fr.Element) fr.Element {
+	var wj fr.Element
+	wj.Exp(domain.Generator, new(big.Int).SetUint64(j))
+	if zeta.Equal(&wj) {
+		return fr.One()
+	}
+	var den fr.Element
+	den.Sub(&zeta, &wj).Inverse(&den)
+	res := polynomial.EvaluateVanishing(domain, zeta)
+	res.Mul(&res, &den).Mul(&res, &wj).Mul(&res, &domain.CardinalityInv)
+	return res
+}
+
+// checkColumns returns an error if the columns don't have the size of the domain
+func checkColumns(domain *fft.Domain, columns ...[]fr.Element) error {
+	for _, c := range columns {
+		if uint64(len(c)) != domain.Cardinality {
+			return ErrInvalidSize
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package iop
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+)
+
+func randomVector(n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func toCanonical(domain *fft.Domain, evaluations []fr.Element) polynomial.Polynomial {
+	lp, err := polynomial.NewLagrangePolynomial(domain, evaluations)
+	if err != nil {
+		panic(err)
+	}
+	return lp.ToCanonical()
+}
+
+// checkContribution checks that the quotient interpolated from its evaluations on the coset has
+// a degree smaller than bound, and that its value at zeta is the one computed by the verifier
+func checkContribution(t *testing.T, bigDomain *fft.Domain, evaluations []fr.Element, bound int, zeta, expected fr.Element) {
+	t.Helper()
+	q := CosetToCanonical(bigDomain, evaluations)
+	for i := bound; i < len(q); i++ {
+		if !q[i].IsZero() {
+			t.Fatalf("the quotient has a degree >= %d", bound)
+		}
+	}
+	v := q.Evaluate(zeta)
+	if !v.Equal(&expected) {
+		t.Fatal("the quotient doesn't match the value computed by the verifier")
+	}
+}
+
+// isPolynomial returns true if the evaluations on the coset are the ones of a polynomial of degree < bound
+func isPolynomial(bigDomain *fft.Domain, evaluations []fr.Element, bound int) bool {
+	q := CosetToCanonical(bigDomain, evaluations)
+	for i := bound; i < len(q); i++ {
+		if !q[i].IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCosetToCanonical(t *testing.T) {
+
+	bigDomain := fft.NewDomain(32, 1, false)
+	p := polynomial.Polynomial(randomVector(32))
+
+	// evaluations at g*W**i
+	evaluations := make([]fr.Element, 32)
+	x := bigDomain.FinerGenerator
+	for i := 0; i < len(evaluations); i++ {
+		evaluations[i] = p.Evaluate(x)
+		x.Mul(&x, &bigDomain.Generator)
+	}
+
+	if !CosetToCanonical(bigDomain, evaluations).Equal(p) {
+		t.Fatal("wrong interpolation on the coset")
+	}
+}
+
+func TestCoset(t *testing.T) {
+
+	domain := fft.NewDomain(8, 0, false)
+	bigDomain := fft.NewDomain(32, 1, false)
+	c, err := newCoset(domain, bigDomain, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newCoset(domain, bigDomain, 5); err != ErrInvalidQuotientDomain {
+		t.Fatal("the domain of the quotient should be too small")
+	}
+
+	// evaluations of p and p(w*X)
+	p := polynomial.Polynomial(randomVector(8))
+	evaluations, err := c.evaluate(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := 0; k < len(c.points); k++ {
+		var wx fr.Element
+		wx.Mul(&c.points[k], &domain.Generator)
+		expected := p.Evaluate(c.points[k])
+		if !evaluations[k].Equal(&expected) {
+			t.Fatal("wrong evaluation on the coset")
+		}
+		expected = p.Evaluate(wx)
+		if !evaluations[c.next(k)].Equal(&expected) {
+			t.Fatal("wrong evaluation of p(w*X) on the coset")
+		}
+	}
+
+	// Lagrange polynomials, on the coset and on the domain
+	one := fr.One()
+	for _, j := range []uint64{0, 3, 7} {
+		l := c.lagrange(j)
+		for k := 0; k < len(c.points); k++ {
+			expected := evaluateLagrange(domain, j, c.points[k])
+			if !l[k].Equal(&expected) {
+				t.Fatal("wrong evaluation of a Lagrange polynomial on the coset")
+			}
+		}
+		for i := uint64(0); i < domain.Cardinality; i++ {
+			var x fr.Element
+			x.Exp(domain.Generator, new(big.Int).SetUint64(i))
+			v := evaluateLagrange(domain, j, x)
+			if (i == j && !v.Equal(&one)) || (i != j && !v.IsZero()) {
+				t.Fatal("wrong evaluation of a Lagrange polynomial on the domain")
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package iop
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var ErrInvalidChallenge = errors.New("beta is the opposite of a value of the columns")
+
+// LogUpOpening evaluations at zeta and w*zeta of the polynomials of the logUp argument,
+// used by the verifier
+type LogUpOpening struct {
+	F, T, M         fr.Element // f(zeta), t(zeta), m(zeta)
+	Phi, PhiShifted fr.Element // phi(zeta), phi(w*zeta)
+}
+
+// Multiplicities returns the multiplicities m of the queries f in the table, m_i being the number
+// of occurrences of t_i in f if i is the first occurrence of t_i in the table, and 0 otherwise.
+// ErrNotSatisfied is returned if a query is not in the table.
+func (t *Table) Multiplicities(f []fr.Element) ([]fr.Element, error) {
+	if err := checkColumns(t.Domain, f); err != nil {
+		return nil, err
+	}
+
+	index := t.index()
+	count := make([]uint64, len(t.Values))
+	for _, v := range f {
+		i, ok := index[v]
+		if !ok {
+			return nil, ErrNotSatisfied
+		}
+		count[i]++
+	}
+
+	res := make([]fr.Element, len(count))
+	for i := 0; i < len(count); i++ {
+		res[i].SetUint64(count[i])
+	}
+	return res, nil
+}
+
+// LogUpAccumulator returns the evaluations on the domain of the accumulator phi of the logarithmic
+// derivative lookup argument (https://eprint.iacr.org/2022/1530), phi(1) = 0 and
+// phi(w**(i+1)) = phi(w**i) + m_i/(beta + t_i) - 1/(beta + f_i).
+//
+// The queries are in the table if and only if (with high probability on beta) Sum_i m_i/(beta + t_i) = Sum_i 1/(beta + f_i),
+// that is phi(w**n) = phi(1), ErrNotSatisfied is returned otherwise. ErrInvalidChallenge is returned if
+// beta + t_i or beta + f_i is zero.
+func (t *Table) LogUpAccumulator(f, m []fr.Element, beta fr.Element) ([]fr.Element, error) {
+	if err := checkColumns(t.Domain, f, m); err != nil {
+		return nil, err
+	}
+	n := len(t.Values)
+
+	// 1/(beta + t_i) and 1/(beta + f_i)
+	inv := make([]fr.Element, 2*n)
+	for i := 0; i < n; i++ {
+		inv[i].Add(&beta, &t.Values[i])
+		inv[n+i].Add(&beta, &f[i])
+	}
+	for i := 0; i < len(inv); i++ {
+		if inv[i].IsZero() {
+			return nil, ErrInvalidChallenge
+		}
+	}
+	inv = polynomial.BatchInvertOrZero(inv)
+
+	phi := make([]fr.Element, n)
+	var u fr.Element
+	for i := 0; i < n-1; i++ {
+		u.Mul(&m[i], &inv[i]).Sub(&u, &inv[n+i])
+		phi[i+1].Add(&phi[i], &u)
+	}
+	u.Mul(&m[n-1], &inv[n-1]).Sub(&u, &inv[2*n-1]).Add(&u, &phi[n-1])
+	if !u.IsZero() {
+		return nil, ErrNotSatisfied
+	}
+
+	return phi, nil
+}
+
+// LogUpQuotientContribution returns the evaluations on the coset of bigDomain of the contribution
+// of the logUp argument to the quotient, (identity_0(X) + alpha*identity_1(X))/Z_H(X) with the identities
+//   - L_0(X)*phi(X)
+//   - (phi(w*X) - phi(X))*(beta + t(X))*(beta + f(X)) - m(X)*(beta + f(X)) + (beta + t(X))
+//
+// from f, m and phi in canonical form. bigDomain must have a depth >= 1, and a cardinality at least 2n
+// so that the quotient, of degree < 2n, can be recovered with CosetToCanonical.
+func (t *Table) LogUpQuotientContribution(bigDomain *fft.Domain, f, m, phi polynomial.Polynomial, beta, alpha fr.Element) ([]fr.Element, error) {
+	c, err := newCoset(t.Domain, bigDomain, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluations := make([][]fr.Element, 4)
+	for i, p := range []polynomial.Polynomial{f, t.Polynomial(), m, phi} {
+		if evaluations[i], err = c.evaluate(p); err != nil {
+			return nil, err
+		}
+	}
+	fEvaluations, tEvaluations, mEvaluations, phiEvaluations := evaluations[0], evaluations[1], evaluations[2], evaluations[3]
+	l0 := c.lagrange(0)
+
+	res := make([]fr.Element, len(c.points))
+	parallel.Execute(len(res), func(start, end int) {
+		var bt, bf, u fr.Element
+		for k := start; k < end; k++ {
+			bt.Add(&beta, &tEvaluations[k])
+			bf.Add(&beta, &fEvaluations[k])
+			res[k].Sub(&phiEvaluations[c.next(k)], &phiEvaluations[k]).Mul(&res[k], &bt).Mul(&res[k], &bf)
+			u.Mul(&mEvaluations[k], &bf)
+			res[k].Sub(&res[k], &u).Add(&res[k], &bt).Mul(&res[k], &alpha)
+			u.Mul(&phiEvaluations[k], &l0[k])
+			res[k].Add(&res[k], &u)
+		}
+	})
+	c.divideByVanishing(res)
+
+	return res, nil
+}
+
+// EvaluateLogUpContribution returns the evaluation at zeta of the contribution of the logUp
+// argument to the quotient, computed by the verifier from the openings of the polynomials at zeta
+// and w*zeta. zeta must not be in the domain.
+func EvaluateLogUpContribution(domain *fft.Domain, zeta fr.Element, opening *LogUpOpening, beta, alpha fr.Element) fr.Element {
+	var res, bt, bf, u fr.Element
+	bt.Add(&beta, &opening.T)
+	bf.Add(&beta, &opening.F)
+	res.Sub(&opening.PhiShifted, &opening.Phi).Mul(&res, &bt).Mul(&res, &bf)
+	u.Mul(&opening.M, &bf)
+	res.Sub(&res, &u).Add(&res, &bt).Mul(&res, &alpha)
+
+	l0 := polynomial.EvaluateFirstLagrange(domain, zeta)
+	u.Mul(&opening.Phi, &l0)
+	res.Add(&res, &u)
+
+	zh := polynomial.EvaluateVanishing(domain, zeta)
+	zh.Inverse(&zh)
+	res.Mul(&res, &zh)
+
+	return res
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package iop
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+)
+
+func TestMultiplicities(t *testing.T) {
+
+	domain := fft.NewDomain(4, 0, false)
+	values := make([]fr.Element, 4)
+	for i := 0; i < 4; i++ {
+		values[i].SetUint64(uint64(10 + i%3))
+	}
+	table, err := NewTable(domain, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the multiplicities are counted at the first occurrence of a value
+	f := []fr.Element{values[3], values[1], values[3], values[0]}
+	m, err := table.Multiplicities(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, c := range []uint64{3, 1, 0, 0} {
+		var e fr.Element
+		e.SetUint64(c)
+		if !m[i].Equal(&e) {
+			t.Fatal("wrong multiplicities")
+		}
+	}
+
+	f[2].SetUint64(1)
+	if _, err := table.Multiplicities(f); err != ErrNotSatisfied {
+		t.Fatal("the query should not be in the table")
+	}
+}
+
+func TestLogUp(t *testing.T) {
+
+	const n = 16
+	domain := fft.NewDomain(n, 0, false)
+	bigDomain := fft.NewDomain(4*n, 1, false)
+
+	table, err := NewTable(domain, randomVector(n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := randomQueries(table.Values, n)
+	m, err := table.Multiplicities(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var beta, alpha, zeta fr.Element
+	beta.SetRandom()
+	alpha.SetRandom()
+	zeta.SetRandom()
+
+	phi, err := table.LogUpAccumulator(f, m, beta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp, mp, phip, tp := toCanonical(domain, f), toCanonical(domain, m), toCanonical(domain, phi), table.Polynomial()
+	evaluations, err := table.LogUpQuotientContribution(bigDomain, fp, mp, phip, beta, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wZeta fr.Element
+	wZeta.Mul(&zeta, &domain.Generator)
+	opening := LogUpOpening{
+		F:   fp.Evaluate(zeta),
+		T:   tp.Evaluate(zeta),
+		M:   mp.Evaluate(zeta),
+		Phi: phip.Evaluate(zeta), PhiShifted: phip.Evaluate(wZeta),
+	}
+	expected := EvaluateLogUpContribution(domain, zeta, &opening, beta, alpha)
+
+	checkContribution(t, bigDomain, evaluations, 2*n, zeta, expected)
+
+	// the accumulator doesn't start at 0
+	for i := 0; i < n; i++ {
+		phi[i].Add(&phi[i], &alpha)
+	}
+	evaluations, err = table.LogUpQuotientContribution(bigDomain, fp, mp, toCanonical(domain, phi), beta, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isPolynomial(bigDomain, evaluations, 2*n) {
+		t.Fatal("the quotient of a wrong accumulator should not be a polynomial")
+	}
+
+	// wrong multiplicities
+	one := fr.One()
+	m[0].Add(&m[0], &one)
+	if _, err := table.LogUpAccumulator(f, m, beta); err != ErrNotSatisfied {
+		t.Fatal("the lookup should not be satisfied")
+	}
+
+	// beta is the opposite of a query
+	beta.Neg(&f[0])
+	if _, err := table.LogUpAccumulator(f, m, beta); err != ErrInvalidChallenge {
+		t.Fatal("beta should be invalid")
+	}
+}
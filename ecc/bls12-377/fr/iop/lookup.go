@@ -0,0 +1,52 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package iop
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+)
+
+// Table lookup table of the size of the domain, shared by the plookup and logUp arguments
+type Table struct {
+	Domain *fft.Domain
+	Values []fr.Element // t(w**i)
+}
+
+// NewTable returns the lookup table of values, which must have the size of the domain
+func NewTable(domain *fft.Domain, values []fr.Element) (*Table, error) {
+	if err := checkColumns(domain, values); err != nil {
+		return nil, err
+	}
+	return &Table{Domain: domain, Values: values}, nil
+}
+
+// Polynomial returns the table polynomial t in canonical form
+func (t *Table) Polynomial() polynomial.Polynomial {
+	lp, _ := polynomial.NewLagrangePolynomial(t.Domain, t.Values)
+	return lp.ToCanonical()
+}
+
+// index returns the index of the first occurrence of each value of the table
+func (t *Table) index() map[fr.Element]int {
+	res := make(map[fr.Element]int, len(t.Values))
+	for i := len(t.Values) - 1; i >= 0; i-- {
+		res[t.Values[i]] = i
+	}
+	return res
+}
@@ -0,0 +1,268 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package iop
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var ErrInvalidPermutation = errors.New("sigma must be a permutation of the positions of the columns")
+
+// Permutation copy constraints between the cells of nbColumns columns of size n, the j-th cell of
+// the i-th column being at the position i*n + j. The constraints are satisfied when the cell at each
+// position p is equal to the cell at the position Sigma[p], Sigma being a permutation whose cycles
+// are the sets of cells which must be equal.
+//
+// Following PLONK (https://eprint.iacr.org/2019/953), the position i*n + j is identified with
+// Shifts[i]*w**j, the Shifts being in distinct cosets of the domain, and Sigma is encoded by the
+// columns S_i, S_i(w**j) being the identifier of the position Sigma[i*n + j].
+type Permutation struct {
+	Domain *fft.Domain
+	Sigma  []int
+	Shifts []fr.Element
+	S      [][]fr.Element
+}
+
+// PermutationOpening evaluations at zeta of the polynomials of the permutation argument,
+// used by the verifier
+type PermutationOpening struct {
+	Columns  []fr.Element // c_i(zeta)
+	S        []fr.Element // S_i(zeta)
+	Z        fr.Element   // Z(zeta)
+	ZShifted fr.Element   // Z(w*zeta)
+}
+
+// NewPermutation returns the copy constraints encoded by sigma on nbColumns columns of the size of the domain
+func NewPermutation(domain *fft.Domain, nbColumns int, sigma []int) (*Permutation, error) {
+	n := int(domain.Cardinality)
+	if nbColumns <= 0 || len(sigma) != nbColumns*n {
+		return nil, ErrInvalidPermutation
+	}
+	seen := make([]bool, len(sigma))
+	for _, p := range sigma {
+		if p < 0 || p >= len(sigma) || seen[p] {
+			return nil, ErrInvalidPermutation
+		}
+		seen[p] = true
+	}
+
+	res := Permutation{
+		Domain: domain,
+		Sigma:  sigma,
+		Shifts: CosetShifts(domain, nbColumns),
+		S:      make([][]fr.Element, nbColumns),
+	}
+
+	// identifiers of all the positions
+	ids := res.identifiers()
+	for i := 0; i < nbColumns; i++ {
+		res.S[i] = make([]fr.Element, n)
+		for j := 0; j < n; j++ {
+			res.S[i][j] = ids[sigma[i*n+j]]
+		}
+	}
+
+	return &res, nil
+}
+
+// CosetShifts returns nbColumns elements in distinct cosets of the group of the domain, the first
+// one being 1. The next ones are the smallest integers u such that u*<w> is a new coset, that is
+// such that (u/v)**n != 1 for the previous shifts v.
+func CosetShifts(domain *fft.Domain, nbColumns int) []fr.Element {
+	n := new(big.Int).SetUint64(domain.Cardinality)
+	res := make([]fr.Element, 1, nbColumns)
+	res[0].SetOne()
+
+	var u, t fr.Element
+	one := fr.One()
+	for c := uint64(2); len(res) < nbColumns; c++ {
+		u.SetUint64(c)
+		distinct := true
+		for k := 0; k < len(res) && distinct; k++ {
+			t.Div(&u, &res[k]).Exp(t, n)
+			distinct = !t.Equal(&one)
+		}
+		if distinct {
+			res = append(res, u)
+		}
+	}
+
+	return res
+}
+
+// SPolynomials returns the polynomials S_i in canonical form
+func (p *Permutation) SPolynomials() []polynomial.Polynomial {
+	res := make([]polynomial.Polynomial, len(p.S))
+	for i := 0; i < len(p.S); i++ {
+		lp, _ := polynomial.NewLagrangePolynomial(p.Domain, p.S[i])
+		res[i] = lp.ToCanonical()
+	}
+	return res
+}
+
+// GrandProduct returns the evaluations on the domain of the grand product polynomial Z,
+// Z(1) = 1 and Z(w**(j+1)) = Z(w**j)*Prod_i (c_i(w**j) + beta*Shifts[i]*w**j + gamma)/(c_i(w**j) + beta*S_i(w**j) + gamma).
+//
+// The columns satisfy the copy constraints if and only if (with high probability on beta and gamma)
+// Z(w**n) = 1, ErrNotSatisfied is returned otherwise.
+func (p *Permutation) GrandProduct(columns [][]fr.Element, beta, gamma fr.Element) ([]fr.Element, error) {
+	if len(columns) != len(p.S) {
+		return nil, ErrInvalidSize
+	}
+	if err := checkColumns(p.Domain, columns...); err != nil {
+		return nil, err
+	}
+	n := int(p.Domain.Cardinality)
+
+	// numerators and denominators of the ratios
+	ids := p.identifiers()
+	num := make([]fr.Element, n)
+	den := make([]fr.Element, n)
+	parallel.Execute(n, func(start, end int) {
+		var t fr.Element
+		for j := start; j < end; j++ {
+			num[j].SetOne()
+			den[j].SetOne()
+			for i := 0; i < len(columns); i++ {
+				t.Mul(&beta, &ids[i*n+j]).Add(&t, &columns[i][j]).Add(&t, &gamma)
+				num[j].Mul(&num[j], &t)
+				t.Mul(&beta, &p.S[i][j]).Add(&t, &columns[i][j]).Add(&t, &gamma)
+				den[j].Mul(&den[j], &t)
+			}
+		}
+	})
+	den = polynomial.BatchInvertOrZero(den)
+
+	z := make([]fr.Element, n)
+	z[0].SetOne()
+	var t fr.Element
+	for j := 0; j < n-1; j++ {
+		t.Mul(&num[j], &den[j])
+		z[j+1].Mul(&z[j], &t)
+	}
+	t.Mul(&num[n-1], &den[n-1]).Mul(&t, &z[n-1])
+	if one := fr.One(); !t.Equal(&one) {
+		return nil, ErrNotSatisfied
+	}
+
+	return z, nil
+}
+
+// QuotientContribution returns the evaluations on the coset of bigDomain of the contribution of the
+// permutation argument to the quotient
+// (L_0(X)*(Z(X) - 1) + alpha*(Z(X)*Prod_i (c_i(X) + beta*Shifts[i]*X + gamma) - Z(w*X)*Prod_i (c_i(X) + beta*S_i(X) + gamma)))/Z_H(X),
+// from the columns and Z in canonical form. bigDomain must have a depth >= 1, and a cardinality at least
+// nbColumns*n so that the quotient, of degree < nbColumns*n, can be recovered with CosetToCanonical.
+func (p *Permutation) QuotientContribution(bigDomain *fft.Domain, columns []polynomial.Polynomial, z polynomial.Polynomial, beta, gamma, alpha fr.Element) ([]fr.Element, error) {
+	if len(columns) != len(p.S) {
+		return nil, ErrInvalidSize
+	}
+	c, err := newCoset(p.Domain, bigDomain, uint64(len(columns)))
+	if err != nil {
+		return nil, err
+	}
+
+	zEvaluations, err := c.evaluate(z)
+	if err != nil {
+		return nil, err
+	}
+	cEvaluations := make([][]fr.Element, len(columns))
+	sEvaluations := make([][]fr.Element, len(columns))
+	s := p.SPolynomials()
+	for i := 0; i < len(columns); i++ {
+		if cEvaluations[i], err = c.evaluate(columns[i]); err != nil {
+			return nil, err
+		}
+		if sEvaluations[i], err = c.evaluate(s[i]); err != nil {
+			return nil, err
+		}
+	}
+	l0 := c.lagrange(0)
+
+	res := make([]fr.Element, len(c.points))
+	parallel.Execute(len(res), func(start, end int) {
+		var num, den, t fr.Element
+		one := fr.One()
+		for k := start; k < end; k++ {
+			num = zEvaluations[k]
+			den = zEvaluations[c.next(k)]
+			for i := 0; i < len(columns); i++ {
+				t.Mul(&beta, &p.Shifts[i]).Mul(&t, &c.points[k]).Add(&t, &cEvaluations[i][k]).Add(&t, &gamma)
+				num.Mul(&num, &t)
+				t.Mul(&beta, &sEvaluations[i][k]).Add(&t, &cEvaluations[i][k]).Add(&t, &gamma)
+				den.Mul(&den, &t)
+			}
+			res[k].Sub(&num, &den).Mul(&res[k], &alpha)
+			t.Sub(&zEvaluations[k], &one).Mul(&t, &l0[k])
+			res[k].Add(&res[k], &t)
+		}
+	})
+	c.divideByVanishing(res)
+
+	return res, nil
+}
+
+// EvaluatePermutationContribution returns the evaluation at zeta of the contribution of the permutation
+// argument to the quotient, computed by the verifier from the openings of the polynomials at zeta
+// and w*zeta. shifts must be CosetShifts(domain, nbColumns), zeta must not be in the domain.
+func EvaluatePermutationContribution(domain *fft.Domain, shifts []fr.Element, zeta fr.Element, opening *PermutationOpening, beta, gamma, alpha fr.Element) (fr.Element, error) {
+	if len(opening.Columns) != len(shifts) || len(opening.S) != len(shifts) {
+		return fr.Element{}, ErrInvalidSize
+	}
+
+	var num, den, t fr.Element
+	num = opening.Z
+	den = opening.ZShifted
+	for i := 0; i < len(shifts); i++ {
+		t.Mul(&beta, &shifts[i]).Mul(&t, &zeta).Add(&t, &opening.Columns[i]).Add(&t, &gamma)
+		num.Mul(&num, &t)
+		t.Mul(&beta, &opening.S[i]).Add(&t, &opening.Columns[i]).Add(&t, &gamma)
+		den.Mul(&den, &t)
+	}
+
+	var res fr.Element
+	res.Sub(&num, &den).Mul(&res, &alpha)
+	one := fr.One()
+	l0 := polynomial.EvaluateFirstLagrange(domain, zeta)
+	t.Sub(&opening.Z, &one).Mul(&t, &l0)
+	res.Add(&res, &t)
+
+	zh := polynomial.EvaluateVanishing(domain, zeta)
+	zh.Inverse(&zh)
+	res.Mul(&res, &zh)
+
+	return res, nil
+}
+
+// identifiers returns the identifiers Shifts[i]*w**j of the positions i*n + j
+func (p *Permutation) identifiers() []fr.Element {
+	n := int(p.Domain.Cardinality)
+	res := make([]fr.Element, len(p.Shifts)*n)
+	for i := 0; i < len(p.Shifts); i++ {
+		res[i*n] = p.Shifts[i]
+		for j := 1; j < n; j++ {
+			res[i*n+j].Mul(&res[i*n+j-1], &p.Domain.Generator)
+		}
+	}
+	return res
+}
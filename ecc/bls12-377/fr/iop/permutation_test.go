@@ -0,0 +1,190 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package iop
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+)
+
+// randomCopyConstraints returns a random permutation of the nbColumns*n positions, and columns
+// satisfying the copy constraints, with a random value on each cycle
+func randomCopyConstraints(n, nbColumns int) ([]int, [][]fr.Element) {
+	sigma := rand.Perm(n * nbColumns)
+	columns := make([][]fr.Element, nbColumns)
+	for i := 0; i < nbColumns; i++ {
+		columns[i] = make([]fr.Element, n)
+	}
+	done := make([]bool, len(sigma))
+	for p := 0; p < len(sigma); p++ {
+		var v fr.Element
+		v.SetRandom()
+		for q := p; !done[q]; q = sigma[q] {
+			columns[q/n][q%n] = v
+			done[q] = true
+		}
+	}
+	return sigma, columns
+}
+
+func TestCosetShifts(t *testing.T) {
+
+	domain := fft.NewDomain(16, 0, false)
+	shifts := CosetShifts(domain, 4)
+	one := fr.One()
+	if len(shifts) != 4 || !shifts[0].Equal(&one) {
+		t.Fatal("wrong shifts")
+	}
+	n := new(big.Int).SetUint64(domain.Cardinality)
+	for i := 0; i < len(shifts); i++ {
+		for j := 0; j < i; j++ {
+			var u fr.Element
+			u.Div(&shifts[i], &shifts[j]).Exp(u, n)
+			if u.Equal(&one) {
+				t.Fatal("the shifts must be in distinct cosets")
+			}
+		}
+	}
+}
+
+func TestNewPermutation(t *testing.T) {
+
+	domain := fft.NewDomain(4, 0, false)
+	for _, sigma := range [][]int{
+		{0, 1, 2, 3, 4, 5, 6},
+		{0, 1, 2, 3, 4, 5, 6, 6},
+		{0, 1, 2, 3, 4, 5, 6, 8},
+	} {
+		if _, err := NewPermutation(domain, 2, sigma); err != ErrInvalidPermutation {
+			t.Fatal("sigma should be invalid")
+		}
+	}
+
+	// identity: S_i is the identity
+	p, err := NewPermutation(domain, 2, []int{0, 1, 2, 3, 4, 5, 6, 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := p.Shifts[1]
+	for j := 0; j < 4; j++ {
+		if !p.S[1][j].Equal(&x) {
+			t.Fatal("wrong identifier")
+		}
+		x.Mul(&x, &domain.Generator)
+	}
+}
+
+func TestPermutation(t *testing.T) {
+
+	const n, nbColumns = 16, 3
+	domain := fft.NewDomain(n, 0, false)
+	bigDomain := fft.NewDomain(4*n, 1, false)
+
+	sigma, columns := randomCopyConstraints(n, nbColumns)
+	p, err := NewPermutation(domain, nbColumns, sigma)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var beta, gamma, alpha, zeta fr.Element
+	beta.SetRandom()
+	gamma.SetRandom()
+	alpha.SetRandom()
+	zeta.SetRandom()
+
+	z, err := p.GrandProduct(columns, beta, gamma)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zp := toCanonical(domain, z)
+	cp := make([]polynomial.Polynomial, nbColumns)
+	for i := 0; i < nbColumns; i++ {
+		cp[i] = toCanonical(domain, columns[i])
+	}
+	evaluations, err := p.QuotientContribution(bigDomain, cp, zp, beta, gamma, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// opening at zeta
+	var opening PermutationOpening
+	var wZeta fr.Element
+	wZeta.Mul(&zeta, &domain.Generator)
+	for i, s := range p.SPolynomials() {
+		opening.Columns = append(opening.Columns, cp[i].Evaluate(zeta))
+		opening.S = append(opening.S, s.Evaluate(zeta))
+	}
+	opening.Z = zp.Evaluate(zeta)
+	opening.ZShifted = zp.Evaluate(wZeta)
+	expected, err := EvaluatePermutationContribution(domain, p.Shifts, zeta, &opening, beta, gamma, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkContribution(t, bigDomain, evaluations, nbColumns*n, zeta, expected)
+
+	// a wrong grand product is not divisible by Z_H
+	z[1].SetRandom()
+	evaluations, err = p.QuotientContribution(bigDomain, cp, toCanonical(domain, z), beta, gamma, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isPolynomial(bigDomain, evaluations, nbColumns*n) {
+		t.Fatal("the quotient of a wrong grand product should not be a polynomial")
+	}
+
+	// the columns don't satisfy the copy constraints anymore
+	for q := 0; q < len(sigma); q++ {
+		if sigma[q] != q {
+			columns[q/n][q%n].SetRandom()
+			break
+		}
+	}
+	if _, err := p.GrandProduct(columns, beta, gamma); err != ErrNotSatisfied {
+		t.Fatal("the copy constraints should not be satisfied")
+	}
+}
+
+func BenchmarkPermutationQuotientContribution(b *testing.B) {
+
+	const n, nbColumns = 1 << 14, 3
+	domain := fft.NewDomain(n, 0, false)
+	bigDomain := fft.NewDomain(4*n, 1, false)
+
+	sigma, columns := randomCopyConstraints(n, nbColumns)
+	p, _ := NewPermutation(domain, nbColumns, sigma)
+	var beta, gamma, alpha fr.Element
+	beta.SetRandom()
+	gamma.SetRandom()
+	alpha.SetRandom()
+	z, _ := p.GrandProduct(columns, beta, gamma)
+	zp := toCanonical(domain, z)
+	cp := make([]polynomial.Polynomial, nbColumns)
+	for i := 0; i < nbColumns; i++ {
+		cp[i] = toCanonical(domain, columns[i])
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.QuotientContribution(bigDomain, cp, zp, beta, gamma, alpha)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package iop
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// PlookupOpening evaluations at zeta and w*zeta of the polynomials of the plookup argument,
+// used by the verifier
+type PlookupOpening struct {
+	F             fr.Element // f(zeta)
+	T, TShifted   fr.Element // t(zeta), t(w*zeta)
+	H1, H1Shifted fr.Element // h1(zeta), h1(w*zeta)
+	H2, H2Shifted fr.Element // h2(zeta), h2(w*zeta)
+	Z, ZShifted   fr.Element // Z(zeta), Z(w*zeta)
+}
+
+// PlookupSorted returns the halves h1 and h2 of the vector s, of size 2n-1, made of the values of the
+// table and of the queries f[:n-1], sorted by the table: each value of the table is followed by its
+// occurrences in f. h1 = s[:n] and h2 = s[n-1:] overlap on one value.
+//
+// As in plookup (https://eprint.iacr.org/2020/315) the last query f[n-1] is not constrained, the
+// queries being defined on the domain without its last point. ErrNotSatisfied is returned if a query
+// is not in the table.
+func (t *Table) PlookupSorted(f []fr.Element) (h1, h2 []fr.Element, err error) {
+	if err := checkColumns(t.Domain, f); err != nil {
+		return nil, nil, err
+	}
+	n := len(t.Values)
+
+	index := t.index()
+	count := make([]int, n)
+	for _, v := range f[:n-1] {
+		i, ok := index[v]
+		if !ok {
+			return nil, nil, ErrNotSatisfied
+		}
+		count[i]++
+	}
+
+	s := make([]fr.Element, 0, 2*n-1)
+	for i := 0; i < n; i++ {
+		s = append(s, t.Values[i])
+		for j := 0; j < count[i]; j++ {
+			s = append(s, t.Values[i])
+		}
+	}
+
+	return s[:n], s[n-1:], nil
+}
+
+// PlookupGrandProduct returns the evaluations on the domain of the grand product polynomial Z,
+// Z(1) = 1 and
+// Z(w**(i+1)) = Z(w**i)*(1+beta)*(gamma+f_i)*(gamma*(1+beta)+t_i+beta*t_{i+1}) / ((gamma*(1+beta)+h1_i+beta*h1_{i+1})*(gamma*(1+beta)+h2_i+beta*h2_{i+1})),
+// for i < n-1. The queries are in the table if and only if (with high probability on beta and gamma)
+// Z(w**(n-1)) = 1, ErrNotSatisfied is returned otherwise.
+func (t *Table) PlookupGrandProduct(f, h1, h2 []fr.Element, beta, gamma fr.Element) ([]fr.Element, error) {
+	if err := checkColumns(t.Domain, f, h1, h2); err != nil {
+		return nil, err
+	}
+	n := len(t.Values)
+
+	var onePlusBeta, gammaOnePlusBeta fr.Element
+	onePlusBeta.SetOne().Add(&onePlusBeta, &beta)
+	gammaOnePlusBeta.Mul(&gamma, &onePlusBeta)
+
+	num := make([]fr.Element, n-1)
+	den := make([]fr.Element, n-1)
+	parallel.Execute(n-1, func(start, end int) {
+		var u fr.Element
+		for i := start; i < end; i++ {
+			num[i].Add(&gamma, &f[i]).Mul(&num[i], &onePlusBeta)
+			u = plookupFactor(&gammaOnePlusBeta, &beta, &t.Values[i], &t.Values[i+1])
+			num[i].Mul(&num[i], &u)
+			den[i] = plookupFactor(&gammaOnePlusBeta, &beta, &h1[i], &h1[i+1])
+			u = plookupFactor(&gammaOnePlusBeta, &beta, &h2[i], &h2[i+1])
+			den[i].Mul(&den[i], &u)
+		}
+	})
+	den = polynomial.BatchInvertOrZero(den)
+
+	z := make([]fr.Element, n)
+	z[0].SetOne()
+	var u fr.Element
+	for i := 0; i < n-1; i++ {
+		u.Mul(&num[i], &den[i])
+		z[i+1].Mul(&z[i], &u)
+	}
+	if one := fr.One(); !z[n-1].Equal(&one) {
+		return nil, ErrNotSatisfied
+	}
+
+	return z, nil
+}
+
+// PlookupQuotientContribution returns the evaluations on the coset of bigDomain of the contribution
+// of the plookup argument to the quotient, (Sum_k alpha**k*identity_k(X))/Z_H(X) with the identities
+//   - L_0(X)*(Z(X) - 1)
+//   - (X - w**(n-1))*(Z(X)*(1+beta)*(gamma+f(X))*(gamma*(1+beta)+t(X)+beta*t(w*X)) - Z(w*X)*(gamma*(1+beta)+h1(X)+beta*h1(w*X))*(gamma*(1+beta)+h2(X)+beta*h2(w*X)))
+//   - L_{n-1}(X)*(h1(X) - h2(w*X))
+//   - L_{n-1}(X)*(Z(X) - 1)
+//
+// from f, h1, h2 and Z in canonical form. bigDomain must have a depth >= 1, and a cardinality at least 3n
+// so that the quotient, of degree < 3n, can be recovered with CosetToCanonical.
+func (t *Table) PlookupQuotientContribution(bigDomain *fft.Domain, f, h1, h2, z polynomial.Polynomial, beta, gamma, alpha fr.Element) ([]fr.Element, error) {
+	c, err := newCoset(t.Domain, bigDomain, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluations := make([][]fr.Element, 5)
+	for i, p := range []polynomial.Polynomial{f, t.Polynomial(), h1, h2, z} {
+		if evaluations[i], err = c.evaluate(p); err != nil {
+			return nil, err
+		}
+	}
+	fEvaluations, tEvaluations, h1Evaluations, h2Evaluations, zEvaluations := evaluations[0], evaluations[1], evaluations[2], evaluations[3], evaluations[4]
+	l0 := c.lagrange(0)
+	lLast := c.lagrange(t.Domain.Cardinality - 1)
+
+	var onePlusBeta, gammaOnePlusBeta, alpha2, alpha3 fr.Element
+	onePlusBeta.SetOne().Add(&onePlusBeta, &beta)
+	gammaOnePlusBeta.Mul(&gamma, &onePlusBeta)
+	alpha2.Square(&alpha)
+	alpha3.Mul(&alpha2, &alpha)
+
+	res := make([]fr.Element, len(c.points))
+	parallel.Execute(len(res), func(start, end int) {
+		var num, den, u fr.Element
+		one := fr.One()
+		for k := start; k < end; k++ {
+			next := c.next(k)
+
+			// L_0(X)*(Z(X) - 1)
+			res[k].Sub(&zEvaluations[k], &one).Mul(&res[k], &l0[k])
+
+			// (X - w**(n-1))*(...)
+			num.Add(&gamma, &fEvaluations[k]).Mul(&num, &onePlusBeta).Mul(&num, &zEvaluations[k])
+			u = plookupFactor(&gammaOnePlusBeta, &beta, &tEvaluations[k], &tEvaluations[next])
+			num.Mul(&num, &u)
+			den = plookupFactor(&gammaOnePlusBeta, &beta, &h1Evaluations[k], &h1Evaluations[next])
+			u = plookupFactor(&gammaOnePlusBeta, &beta, &h2Evaluations[k], &h2Evaluations[next])
+			den.Mul(&den, &u).Mul(&den, &zEvaluations[next])
+			num.Sub(&num, &den)
+			u.Sub(&c.points[k], &t.Domain.GeneratorInv)
+			num.Mul(&num, &u).Mul(&num, &alpha)
+			res[k].Add(&res[k], &num)
+
+			// L_{n-1}(X)*(h1(X) - h2(w*X))
+			u.Sub(&h1Evaluations[k], &h2Evaluations[next]).Mul(&u, &lLast[k]).Mul(&u, &alpha2)
+			res[k].Add(&res[k], &u)
+
+			// L_{n-1}(X)*(Z(X) - 1)
+			u.Sub(&zEvaluations[k], &one).Mul(&u, &lLast[k]).Mul(&u, &alpha3)
+			res[k].Add(&res[k], &u)
+		}
+	})
+	c.divideByVanishing(res)
+
+	return res, nil
+}
+
+// EvaluatePlookupContribution returns the evaluation at zeta of the contribution of the plookup
+// argument to the quotient, computed by the verifier from the openings of the polynomials at zeta
+// and w*zeta. zeta must not be in the domain.
+func EvaluatePlookupContribution(domain *fft.Domain, zeta fr.Element, opening *PlookupOpening, beta, gamma, alpha fr.Element) fr.Element {
+	var onePlusBeta, gammaOnePlusBeta fr.Element
+	onePlusBeta.SetOne().Add(&onePlusBeta, &beta)
+	gammaOnePlusBeta.Mul(&gamma, &onePlusBeta)
+	one := fr.One()
+	l0 := polynomial.EvaluateFirstLagrange(domain, zeta)
+	lLast := evaluateLagrange(domain, domain.Cardinality-1, zeta)
+
+	var res, num, den, u fr.Element
+	res.Sub(&opening.Z, &one).Mul(&res, &l0)
+
+	num.Add(&gamma, &opening.F).Mul(&num, &onePlusBeta).Mul(&num, &opening.Z)
+	u = plookupFactor(&gammaOnePlusBeta, &beta, &opening.T, &opening.TShifted)
+	num.Mul(&num, &u)
+	den = plookupFactor(&gammaOnePlusBeta, &beta, &opening.H1, &opening.H1Shifted)
+	u = plookupFactor(&gammaOnePlusBeta, &beta, &opening.H2, &opening.H2Shifted)
+	den.Mul(&den, &u).Mul(&den, &opening.ZShifted)
+	num.Sub(&num, &den)
+	u.Sub(&zeta, &domain.GeneratorInv)
+	num.Mul(&num, &u).Mul(&num, &alpha)
+	res.Add(&res, &num)
+
+	var alphaPow fr.Element
+	alphaPow.Square(&alpha)
+	u.Sub(&opening.H1, &opening.H2Shifted).Mul(&u, &lLast).Mul(&u, &alphaPow)
+	res.Add(&res, &u)
+
+	alphaPow.Mul(&alphaPow, &alpha)
+	u.Sub(&opening.Z, &one).Mul(&u, &lLast).Mul(&u, &alphaPow)
+	res.Add(&res, &u)
+
+	zh := polynomial.EvaluateVanishing(domain, zeta)
+	zh.Inverse(&zh)
+	res.Mul(&res, &zh)
+
+	return res
+}
+
+// plookupFactor returns gamma*(1+beta) + a + beta*b
+func plookupFactor(gammaOnePlusBeta, beta, a, b *fr.Element) fr.Element {
+	var res fr.Element
+	res.Mul(beta, b).Add(&res, a).Add(&res, gammaOnePlusBeta)
+	return res
+}
@@ -0,0 +1,131 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package iop
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+)
+
+// randomQueries returns n random values of the table
+func randomQueries(table []fr.Element, n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		res[i] = table[rand.Intn(len(table))]
+	}
+	return res
+}
+
+func TestPlookupSorted(t *testing.T) {
+
+	domain := fft.NewDomain(4, 0, false)
+	values := make([]fr.Element, 4)
+	for i := 0; i < 4; i++ {
+		values[i].SetUint64(uint64(10 + i))
+	}
+	table, err := NewTable(domain, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the last query is not constrained
+	f := []fr.Element{values[2], values[0], values[2], {}}
+	h1, h2, err := table.PlookupSorted(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []uint64{10, 10, 11, 12, 12, 12, 13}
+	for i := 0; i < 4; i++ {
+		var e1, e2 fr.Element
+		e1.SetUint64(expected[i])
+		e2.SetUint64(expected[3+i])
+		if !h1[i].Equal(&e1) || !h2[i].Equal(&e2) {
+			t.Fatal("wrong sorted vector")
+		}
+	}
+
+	f[3] = f[1]
+	f[1].SetUint64(1)
+	if _, _, err := table.PlookupSorted(f); err != ErrNotSatisfied {
+		t.Fatal("the query should not be in the table")
+	}
+}
+
+func TestPlookup(t *testing.T) {
+
+	const n = 16
+	domain := fft.NewDomain(n, 0, false)
+	bigDomain := fft.NewDomain(4*n, 1, false)
+
+	table, err := NewTable(domain, randomVector(n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := randomQueries(table.Values, n)
+	h1, h2, err := table.PlookupSorted(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var beta, gamma, alpha, zeta fr.Element
+	beta.SetRandom()
+	gamma.SetRandom()
+	alpha.SetRandom()
+	zeta.SetRandom()
+
+	z, err := table.PlookupGrandProduct(f, h1, h2, beta, gamma)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp, h1p, h2p, zp, tp := toCanonical(domain, f), toCanonical(domain, h1), toCanonical(domain, h2), toCanonical(domain, z), table.Polynomial()
+	evaluations, err := table.PlookupQuotientContribution(bigDomain, fp, h1p, h2p, zp, beta, gamma, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wZeta fr.Element
+	wZeta.Mul(&zeta, &domain.Generator)
+	opening := PlookupOpening{
+		F: fp.Evaluate(zeta),
+		T: tp.Evaluate(zeta), TShifted: tp.Evaluate(wZeta),
+		H1: h1p.Evaluate(zeta), H1Shifted: h1p.Evaluate(wZeta),
+		H2: h2p.Evaluate(zeta), H2Shifted: h2p.Evaluate(wZeta),
+		Z: zp.Evaluate(zeta), ZShifted: zp.Evaluate(wZeta),
+	}
+	expected := EvaluatePlookupContribution(domain, zeta, &opening, beta, gamma, alpha)
+
+	checkContribution(t, bigDomain, evaluations, 3*n, zeta, expected)
+
+	// h2 doesn't start with the end of h1
+	h2[0].SetRandom()
+	evaluations, err = table.PlookupQuotientContribution(bigDomain, fp, h1p, toCanonical(domain, h2), zp, beta, gamma, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isPolynomial(bigDomain, evaluations, 3*n) {
+		t.Fatal("the quotient of a wrong sorted vector should not be a polynomial")
+	}
+
+	// a value of the sorted vector is not in the table
+	h1[3].SetRandom()
+	if _, err := table.PlookupGrandProduct(f, h1, h2, beta, gamma); err != ErrNotSatisfied {
+		t.Fatal("the lookup should not be satisfied")
+	}
+}
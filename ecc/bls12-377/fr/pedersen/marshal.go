@@ -0,0 +1,132 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"io"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// WriteTo writes binary encoding of the key
+func (key *Key) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&key.H,
+		key.Basis,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes key data from reader.
+func (key *Key) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&key.H,
+		&key.Basis,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of a Commitment
+func (c *Commitment) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+	err := enc.Encode((*bls12377.G1Affine)(c))
+	return enc.BytesWritten(), err
+}
+
+// ReadFrom decodes a Commitment from reader
+func (c *Commitment) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+	err := dec.Decode((*bls12377.G1Affine)(c))
+	return dec.BytesRead(), err
+}
+
+// Bytes returns the compressed binary encoding of a Commitment
+func (c *Commitment) Bytes() []byte {
+	b := (*bls12377.G1Affine)(c).Bytes()
+	return b[:]
+}
+
+// WriteTo writes binary encoding of a ProofOfKnowledge
+func (proof *ProofOfKnowledge) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&proof.T,
+		&proof.ZBlinding,
+		uint64(len(proof.Z)),
+	}
+	for i := 0; i < len(proof.Z); i++ {
+		toEncode = append(toEncode, &proof.Z[i])
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes a ProofOfKnowledge from reader
+func (proof *ProofOfKnowledge) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+
+	var nbValues uint64
+	toDecode := []interface{}{
+		&proof.T,
+		&proof.ZBlinding,
+		&nbValues,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	// values are read one by one, so that a corrupted length fails at the end of the data
+	proof.Z = make([]fr.Element, 0)
+	for i := uint64(0); i < nbValues; i++ {
+		var z fr.Element
+		if err := dec.Decode(&z); err != nil {
+			return dec.BytesRead(), err
+		}
+		proof.Z = append(proof.Z, z)
+	}
+
+	return dec.BytesRead(), nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidSize     = errors.New("the number of generators must be positive")
+	ErrInvalidNbValues = errors.New("the number of values is larger than the number of generators")
+	ErrInvalidOpening  = errors.New("the commitment doesn't open to the values")
+	ErrInvalidProof    = errors.New("invalid proof of knowledge of the opening")
+)
+
+// Key stores the generators of the commitments
+type Key struct {
+	Basis []bls12377.G1Affine // generators used to commit to the values
+	H     bls12377.G1Affine   // generator used to commit to the blinding factor
+}
+
+// Commitment Pedersen commitment to a vector of values v: Sum_i v_i*Basis[i] + r*H,
+// r being the blinding factor.
+type Commitment bls12377.G1Affine
+
+// ProofOfKnowledge proof of knowledge of the opening (v, r) of a commitment C.
+//
+// It's a sigma protocol made non interactive using Fiat Shamir: with t, s random
+// masks, T = Sum_i t_i*Basis[i] + s*H, and e the challenge,
+// Z = t + e*v, ZBlinding = s + e*r so that Sum_i Z_i*Basis[i] + ZBlinding*H = T + e*C.
+type ProofOfKnowledge struct {
+
+	// T commitment to the masks
+	T bls12377.G1Affine
+
+	// Z masked values
+	Z []fr.Element
+
+	// ZBlinding masked blinding factor
+	ZBlinding fr.Element
+}
+
+// NewKey returns a key with size generators for the values, derived from the domain
+// separation tag dst with HashToCurveG1Svdw.
+//
+// Keys built from different tags are independent, keys built from the same tag
+// share their first generators.
+func NewKey(size int, dst []byte) (*Key, error) {
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+
+	var key Key
+	key.Basis = make([]bls12377.G1Affine, size)
+
+	var chErr = make(chan error, 1)
+	parallel.Execute(size, func(start, end int) {
+		var msg [9]byte
+		msg[0] = 'G'
+		for i := start; i < end; i++ {
+			binary.BigEndian.PutUint64(msg[1:], uint64(i))
+			g, err := bls12377.HashToCurveG1Svdw(msg[:], dst)
+			if err != nil {
+				select {
+				case chErr <- err:
+				default:
+				}
+				return
+			}
+			key.Basis[i] = g
+		}
+	})
+	select {
+	case err := <-chErr:
+		return nil, err
+	default:
+	}
+
+	var err error
+	key.H, err = bls12377.HashToCurveG1Svdw([]byte{'H'}, dst)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// Commit commits to values, with the blinding factor blinding.
+// values can be shorter than the key, it is then padded with zeros.
+//
+// The commitment is perfectly hiding only if the blinding factor is random, it
+// must then be kept to open the commitment.
+func (key *Key) Commit(values []fr.Element, blinding *fr.Element) (Commitment, error) {
+	if len(values) > len(key.Basis) {
+		return Commitment{}, ErrInvalidNbValues
+	}
+
+	points := make([]bls12377.G1Affine, len(values)+1)
+	copy(points, key.Basis[:len(values)])
+	points[len(values)] = key.H
+	scalars := make([]fr.Element, len(values)+1)
+	copy(scalars, values)
+	scalars[len(values)] = *blinding
+
+	var res bls12377.G1Affine
+	res.MultiExp(points, toRegular(scalars))
+
+	return Commitment(res), nil
+}
+
+// VerifyOpening checks that the commitment c opens to values with the blinding factor blinding
+func (key *Key) VerifyOpening(c *Commitment, values []fr.Element, blinding *fr.Element) error {
+	expected, err := key.Commit(values, blinding)
+	if err != nil {
+		return err
+	}
+	if !expected.Equal(c) {
+		return ErrInvalidOpening
+	}
+	return nil
+}
+
+// ProveKnowledge proves the knowledge of the opening (values, blinding) of the commitment c,
+// without revealing it.
+func (key *Key) ProveKnowledge(c *Commitment, values []fr.Element, blinding *fr.Element) (ProofOfKnowledge, error) {
+	if len(values) > len(key.Basis) {
+		return ProofOfKnowledge{}, ErrInvalidNbValues
+	}
+
+	// commit to random masks
+	masks := make([]fr.Element, len(values))
+	for i := 0; i < len(masks); i++ {
+		if _, err := masks[i].SetRandom(); err != nil {
+			return ProofOfKnowledge{}, err
+		}
+	}
+	var maskBlinding fr.Element
+	if _, err := maskBlinding.SetRandom(); err != nil {
+		return ProofOfKnowledge{}, err
+	}
+
+	var res ProofOfKnowledge
+	t, err := key.Commit(masks, &maskBlinding)
+	if err != nil {
+		return ProofOfKnowledge{}, err
+	}
+	res.T = bls12377.G1Affine(t)
+
+	// derive the challenge
+	e, err := key.deriveChallenge(c, &res.T, len(values))
+	if err != nil {
+		return ProofOfKnowledge{}, err
+	}
+
+	// mask the opening
+	res.Z = make([]fr.Element, len(values))
+	for i := 0; i < len(values); i++ {
+		res.Z[i].Mul(&e, &values[i]).Add(&res.Z[i], &masks[i])
+	}
+	res.ZBlinding.Mul(&e, blinding).Add(&res.ZBlinding, &maskBlinding)
+
+	return res, nil
+}
+
+// VerifyKnowledge verifies a proof of knowledge of the opening of the commitment c.
+// It costs a single multi exponentiation.
+func (key *Key) VerifyKnowledge(c *Commitment, proof *ProofOfKnowledge) error {
+	nbValues := len(proof.Z)
+	if nbValues > len(key.Basis) {
+		return ErrInvalidNbValues
+	}
+
+	e, err := key.deriveChallenge(c, &proof.T, nbValues)
+	if err != nil {
+		return err
+	}
+
+	// Sum_i Z_i*Basis[i] + ZBlinding*H - T - e*C == 0
+	points := make([]bls12377.G1Affine, nbValues+3)
+	copy(points, key.Basis[:nbValues])
+	points[nbValues] = key.H
+	points[nbValues+1] = proof.T
+	points[nbValues+2] = bls12377.G1Affine(*c)
+	scalars := make([]fr.Element, nbValues+3)
+	copy(scalars, proof.Z)
+	scalars[nbValues] = proof.ZBlinding
+	scalars[nbValues+1].SetOne().Neg(&scalars[nbValues+1])
+	scalars[nbValues+2].Neg(&e)
+
+	var res bls12377.G1Jac
+	res.MultiExp(points, toRegular(scalars))
+	if !res.Z.IsZero() {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
+
+// Add sets c = a + b and returns c.
+// If a opens to (v, r) and b to (v', r'), c opens to (v + v', r + r').
+func (c *Commitment) Add(a, b *Commitment) *Commitment {
+	var _a, _b bls12377.G1Jac
+	_a.FromAffine((*bls12377.G1Affine)(a))
+	_b.FromAffine((*bls12377.G1Affine)(b))
+	_a.AddAssign(&_b)
+	(*bls12377.G1Affine)(c).FromJacobian(&_a)
+	return c
+}
+
+// Sub sets c = a - b and returns c.
+// If a opens to (v, r) and b to (v', r'), c opens to (v - v', r - r').
+func (c *Commitment) Sub(a, b *Commitment) *Commitment {
+	var _a, _b bls12377.G1Jac
+	_a.FromAffine((*bls12377.G1Affine)(a))
+	_b.FromAffine((*bls12377.G1Affine)(b))
+	_a.SubAssign(&_b)
+	(*bls12377.G1Affine)(c).FromJacobian(&_a)
+	return c
+}
+
+// ScalarMultiplication sets c = s*a and returns c.
+// If a opens to (v, r), c opens to (s*v, s*r).
+func (c *Commitment) ScalarMultiplication(a *Commitment, s *fr.Element) *Commitment {
+	var bi big.Int
+	s.ToBigIntRegular(&bi)
+	(*bls12377.G1Affine)(c).ScalarMultiplication((*bls12377.G1Affine)(a), &bi)
+	return c
+}
+
+// Equal returns true if c and a are the same commitment
+func (c *Commitment) Equal(a *Commitment) bool {
+	return (*bls12377.G1Affine)(c).Equal((*bls12377.G1Affine)(a))
+}
+
+// deriveChallenge derives the challenge of the proof of knowledge, binded to the
+// key, the commitment and the commitment to the masks.
+func (key *Key) deriveChallenge(c *Commitment, t *bls12377.G1Affine, nbValues int) (fr.Element, error) {
+	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, "e")
+
+	var size [8]byte
+	binary.BigEndian.PutUint64(size[:], uint64(nbValues))
+	h := key.H.RawBytes()
+	cBytes := (*bls12377.G1Affine)(c).RawBytes()
+	tBytes := t.RawBytes()
+	for _, b := range [][]byte{size[:], h[:], cBytes[:], tBytes[:]} {
+		if err := fs.Bind("e", b); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	b, err := fs.ComputeChallenge("e")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(b)
+	return res, nil
+}
+
+// toRegular returns a copy of the scalars, converted from Montgomery form
+func toRegular(scalars []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(scalars))
+	parallel.Execute(len(scalars), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i] = scalars[i]
+			res[i].FromMont()
+		}
+	})
+	return res
+}
@@ -0,0 +1,351 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package pedersen
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+var testDST = []byte("GNARK_CRYPTO_PEDERSEN_TEST")
+
+func randomVector(size int) []fr.Element {
+	v := make([]fr.Element, size)
+	for i := 0; i < size; i++ {
+		v[i].SetRandom()
+	}
+	return v
+}
+
+func Example() {
+	// derive 4 generators
+	key, _ := NewKey(4, []byte("example"))
+
+	// commit to a vector, with a random blinding factor
+	values := []fr.Element{fr.One(), fr.One(), fr.One(), fr.One()}
+	var blinding fr.Element
+	blinding.SetRandom()
+	commitment, _ := key.Commit(values, &blinding)
+
+	// prove the knowledge of the opening without revealing it
+	proof, _ := key.ProveKnowledge(&commitment, values, &blinding)
+	if err := key.VerifyKnowledge(&commitment, &proof); err != nil {
+		fmt.Println("1. invalid proof")
+	} else {
+		fmt.Println("1. valid proof")
+	}
+
+	// Output: 1. valid proof
+}
+
+func TestNewKey(t *testing.T) {
+
+	key, err := NewKey(16, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the generators are in G1 and distinct
+	for i := 0; i < len(key.Basis); i++ {
+		if !key.Basis[i].IsInSubGroup() || key.Basis[i].IsInfinity() || key.Basis[i].Equal(&key.H) {
+			t.Fatal("invalid generator")
+		}
+		for j := 0; j < i; j++ {
+			if key.Basis[i].Equal(&key.Basis[j]) {
+				t.Fatal("the generators should be distinct")
+			}
+		}
+	}
+
+	// the generators are deterministic
+	_key, err := NewKey(8, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(key.Basis[:8], _key.Basis) || !key.H.Equal(&_key.H) {
+		t.Fatal("the generators should only depend on the tag")
+	}
+
+	// and depend on the tag
+	_key, err = NewKey(8, []byte("another tag"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.Basis[0].Equal(&_key.Basis[0]) || key.H.Equal(&_key.H) {
+		t.Fatal("the generators should depend on the tag")
+	}
+
+	if _, err := NewKey(0, testDST); err != ErrInvalidSize {
+		t.Fatal("a key without generators should be rejected")
+	}
+}
+
+func TestCommit(t *testing.T) {
+
+	key, err := NewKey(16, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := randomVector(10)
+	var blinding fr.Element
+	blinding.SetRandom()
+
+	commitment, err := key.Commit(values, &blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// check the commitment using a manual sum
+	var manualCommit, tmp bls12377.G1Jac
+	for i := 0; i < len(values); i++ {
+		tmp.FromAffine(&key.Basis[i])
+		tmp.ScalarMultiplication(&tmp, values[i].ToBigIntRegular(new(big.Int)))
+		manualCommit.AddAssign(&tmp)
+	}
+	tmp.FromAffine(&key.H)
+	tmp.ScalarMultiplication(&tmp, blinding.ToBigIntRegular(new(big.Int)))
+	manualCommit.AddAssign(&tmp)
+	var manualCommitAff bls12377.G1Affine
+	manualCommitAff.FromJacobian(&manualCommit)
+
+	if !manualCommitAff.Equal((*bls12377.G1Affine)(&commitment)) {
+		t.Fatal("error Pedersen commitment")
+	}
+
+	// opening
+	if err := key.VerifyOpening(&commitment, values, &blinding); err != nil {
+		t.Fatal(err)
+	}
+	values[3].Double(&values[3])
+	if err := key.VerifyOpening(&commitment, values, &blinding); err != ErrInvalidOpening {
+		t.Fatal("opening to other values should have failed")
+	}
+
+	// too many values
+	if _, err := key.Commit(randomVector(17), &blinding); err != ErrInvalidNbValues {
+		t.Fatal("commitment to more values than generators should have failed")
+	}
+}
+
+func TestHomomorphism(t *testing.T) {
+
+	key, err := NewKey(16, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := randomVector(16), randomVector(16)
+	var ra, rb, s fr.Element
+	ra.SetRandom()
+	rb.SetRandom()
+	s.SetRandom()
+
+	ca, err := key.Commit(a, &ra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb, err := key.Commit(b, &rb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// C(a, ra) + C(b, rb) = C(a + b, ra + rb)
+	sum := make([]fr.Element, len(a))
+	for i := 0; i < len(a); i++ {
+		sum[i].Add(&a[i], &b[i])
+	}
+	var rSum fr.Element
+	rSum.Add(&ra, &rb)
+	var c Commitment
+	c.Add(&ca, &cb)
+	if err := key.VerifyOpening(&c, sum, &rSum); err != nil {
+		t.Fatal("the sum of commitments should open to the sum of the values")
+	}
+
+	// C(a, ra) - C(b, rb) = C(a - b, ra - rb)
+	for i := 0; i < len(a); i++ {
+		sum[i].Sub(&a[i], &b[i])
+	}
+	rSum.Sub(&ra, &rb)
+	c.Sub(&ca, &cb)
+	if err := key.VerifyOpening(&c, sum, &rSum); err != nil {
+		t.Fatal("the difference of commitments should open to the difference of the values")
+	}
+
+	// s*C(a, ra) = C(s*a, s*ra)
+	for i := 0; i < len(a); i++ {
+		sum[i].Mul(&a[i], &s)
+	}
+	rSum.Mul(&ra, &s)
+	c.ScalarMultiplication(&ca, &s)
+	if err := key.VerifyOpening(&c, sum, &rSum); err != nil {
+		t.Fatal("the scaled commitment should open to the scaled values")
+	}
+}
+
+func TestProofOfKnowledge(t *testing.T) {
+
+	key, err := NewKey(16, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := randomVector(12)
+	var blinding fr.Element
+	blinding.SetRandom()
+	commitment, err := key.Commit(values, &blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// correct proof
+	proof, err := key.ProveKnowledge(&commitment, values, &blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := key.VerifyKnowledge(&commitment, &proof); err != nil {
+		t.Fatal(err)
+	}
+
+	// proof for another commitment
+	var other Commitment
+	other.Add(&commitment, &commitment)
+	if err := key.VerifyKnowledge(&other, &proof); err != ErrInvalidProof {
+		t.Fatal("verifying the proof against another commitment should have failed")
+	}
+
+	// proof of a wrong opening
+	values[0].Double(&values[0])
+	proof, err = key.ProveKnowledge(&commitment, values, &blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := key.VerifyKnowledge(&commitment, &proof); err != ErrInvalidProof {
+		t.Fatal("verifying a proof of a wrong opening should have failed")
+	}
+
+	// tampered proof
+	proof, err = key.ProveKnowledge(&other, randomVector(12), &blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof.ZBlinding.Double(&proof.ZBlinding)
+	if err := key.VerifyKnowledge(&other, &proof); err != ErrInvalidProof {
+		t.Fatal("verifying a tampered proof should have failed")
+	}
+}
+
+func TestSerialization(t *testing.T) {
+
+	key, err := NewKey(16, testDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// key
+	var buf bytes.Buffer
+	if _, err := key.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _key Key
+	if _, err := _key.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(key, &_key) {
+		t.Fatal("key serialization failed")
+	}
+
+	// commitment
+	values := randomVector(12)
+	var blinding fr.Element
+	blinding.SetRandom()
+	commitment, err := key.Commit(values, &blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := commitment.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _commitment Commitment
+	if _, err := _commitment.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !commitment.Equal(&_commitment) {
+		t.Fatal("commitment serialization failed")
+	}
+
+	// proof of knowledge
+	proof, err := key.ProveKnowledge(&commitment, values, &blinding)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	written, err := proof.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var _proof ProofOfKnowledge
+	read, err := _proof.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != read || !reflect.DeepEqual(proof, _proof) {
+		t.Fatal("proof serialization failed")
+	}
+}
+
+const benchSize = 1 << 12
+
+func BenchmarkCommit(b *testing.B) {
+	key, err := NewKey(benchSize, testDST)
+	if err != nil {
+		b.Fatal(err)
+	}
+	values := randomVector(benchSize)
+	var blinding fr.Element
+	blinding.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key.Commit(values, &blinding)
+	}
+}
+
+func BenchmarkVerifyKnowledge(b *testing.B) {
+	key, err := NewKey(benchSize, testDST)
+	if err != nil {
+		b.Fatal(err)
+	}
+	values := randomVector(benchSize)
+	var blinding fr.Element
+	blinding.SetRandom()
+	commitment, _ := key.Commit(values, &blinding)
+	proof, _ := key.ProveKnowledge(&commitment, values, &blinding)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key.VerifyKnowledge(&commitment, &proof)
+	}
+}
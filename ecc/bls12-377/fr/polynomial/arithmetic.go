@@ -0,0 +1,249 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+)
+
+var ErrDivisionByZero = errors.New("division by the zero polynomial")
+
+// mulFFTThreshold is the size of the smallest operand from which Mul uses FFTs,
+// below it the schoolbook multiplication is faster
+const mulFFTThreshold = 32
+
+// Clone returns a copy of p
+func (p Polynomial) Clone() Polynomial {
+	res := make(Polynomial, len(p))
+	copy(res, p)
+	return res
+}
+
+// Equal returns true if p and q are the same polynomial, the trailing zero
+// coefficients being ignored
+func (p Polynomial) Equal(q Polynomial) bool {
+	p, q = p.trim(), q.trim()
+	if len(p) != len(q) {
+		return false
+	}
+	for i := 0; i < len(p); i++ {
+		if !p[i].Equal(&q[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsZero returns true if all the coefficients of p are zero
+func (p Polynomial) IsZero() bool {
+	return len(p.trim()) == 0
+}
+
+// Add sets p to p1 + p2 and returns p
+func (p *Polynomial) Add(p1, p2 Polynomial) *Polynomial {
+	n := len(p1)
+	if len(p2) > n {
+		n = len(p2)
+	}
+	res := p.resize(n)
+	for i := 0; i < n; i++ {
+		switch {
+		case i < len(p1) && i < len(p2):
+			res[i].Add(&p1[i], &p2[i])
+		case i < len(p1):
+			res[i] = p1[i]
+		default:
+			res[i] = p2[i]
+		}
+	}
+	*p = res
+	return p
+}
+
+// Sub sets p to p1 - p2 and returns p
+func (p *Polynomial) Sub(p1, p2 Polynomial) *Polynomial {
+	n := len(p1)
+	if len(p2) > n {
+		n = len(p2)
+	}
+	res := p.resize(n)
+	for i := 0; i < n; i++ {
+		switch {
+		case i < len(p1) && i < len(p2):
+			res[i].Sub(&p1[i], &p2[i])
+		case i < len(p1):
+			res[i] = p1[i]
+		default:
+			res[i].Neg(&p2[i])
+		}
+	}
+	*p = res
+	return p
+}
+
+// ScaleBy sets p to c*p1 and returns p
+func (p *Polynomial) ScaleBy(p1 Polynomial, c *fr.Element) *Polynomial {
+	res := p.resize(len(p1))
+	for i := 0; i < len(p1); i++ {
+		res[i].Mul(&p1[i], c)
+	}
+	*p = res
+	return p
+}
+
+// Mul sets p to p1*p2 and returns p.
+//
+// The product is computed with the schoolbook method for small operands, and with FFTs
+// on a fft.Domain of size the next power of 2 of len(p1)+len(p2)-1 otherwise.
+func (p *Polynomial) Mul(p1, p2 Polynomial) *Polynomial {
+	if len(p1) == 0 || len(p2) == 0 {
+		*p = (*p)[:0]
+		return p
+	}
+	if len(p1) < mulFFTThreshold || len(p2) < mulFFTThreshold {
+		*p = mulSchoolbook(p1, p2)
+	} else {
+		*p = mulFFT(p1, p2)
+	}
+	return p
+}
+
+// Div returns the quotient and the remainder of the euclidean division of p by d,
+// such that p = quotient*d + remainder with deg(remainder) < deg(d).
+//
+// The trailing zero coefficients of p and d are ignored, ErrDivisionByZero is returned
+// if d is the zero polynomial.
+func (p Polynomial) Div(d Polynomial) (quotient, remainder Polynomial, err error) {
+	d = d.trim()
+	if len(d) == 0 {
+		return nil, nil, ErrDivisionByZero
+	}
+	remainder = p.trim().Clone()
+	if len(remainder) < len(d) {
+		return Polynomial{}, remainder, nil
+	}
+
+	var lcInv, c, t fr.Element
+	lcInv.Inverse(&d[len(d)-1])
+	quotient = make(Polynomial, len(remainder)-len(d)+1)
+	for i := len(quotient) - 1; i >= 0; i-- {
+		// cancel the coefficient of degree i+deg(d) of the remainder
+		c.Mul(&remainder[i+len(d)-1], &lcInv)
+		quotient[i] = c
+		for j := 0; j < len(d); j++ {
+			t.Mul(&c, &d[j])
+			remainder[i+j].Sub(&remainder[i+j], &t)
+		}
+	}
+
+	return quotient, remainder[:len(d)-1].trim(), nil
+}
+
+// DivideByXMinusA returns the quotient q and the remainder r of the division of p by
+// X - a using synthetic division, that is p = q*(X - a) + r, with r = p(a).
+//
+// When p(a) is known, as for KZG opening proofs, q is the quotient (p - p(a))/(X - a).
+func (p Polynomial) DivideByXMinusA(a fr.Element) (Polynomial, fr.Element) {
+	if len(p) == 0 {
+		return Polynomial{}, fr.Element{}
+	}
+	res := p.Clone()
+
+	// after the loop, res[0] is the remainder and res[1:] the quotient
+	var t fr.Element
+	for i := len(res) - 2; i >= 0; i-- {
+		t.Mul(&res[i+1], &a)
+		res[i].Add(&res[i], &t)
+	}
+
+	return res[1:], res[0]
+}
+
+// Derivative returns the formal derivative of p
+func (p Polynomial) Derivative() Polynomial {
+	if len(p) <= 1 {
+		return Polynomial{}
+	}
+	res := make(Polynomial, len(p)-1)
+	var k fr.Element
+	for i := 1; i < len(p); i++ {
+		k.SetUint64(uint64(i))
+		res[i-1].Mul(&p[i], &k)
+	}
+	return res
+}
+
+// Compose returns the polynomial p(q(X)), computed with Horner's method
+func (p Polynomial) Compose(q Polynomial) Polynomial {
+	res := Polynomial{}
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(res, q)
+		res.Add(res, Polynomial{p[i]})
+	}
+	return res
+}
+
+// trim returns p without its trailing zero coefficients
+func (p Polynomial) trim() Polynomial {
+	n := len(p)
+	for n > 0 && p[n-1].IsZero() {
+		n--
+	}
+	return p[:n]
+}
+
+// resize returns *p resized to n coefficients, reusing its memory when possible.
+// The coefficients are not initialized.
+func (p *Polynomial) resize(n int) Polynomial {
+	if cap(*p) >= n {
+		return (*p)[:n]
+	}
+	return make(Polynomial, n)
+}
+
+func mulSchoolbook(p1, p2 Polynomial) Polynomial {
+	res := make(Polynomial, len(p1)+len(p2)-1)
+	var t fr.Element
+	for i := 0; i < len(p1); i++ {
+		for j := 0; j < len(p2); j++ {
+			t.Mul(&p1[i], &p2[j])
+			res[i+j].Add(&res[i+j], &t)
+		}
+	}
+	return res
+}
+
+func mulFFT(p1, p2 Polynomial) Polynomial {
+	n := len(p1) + len(p2) - 1
+	domain := fft.NewDomain(uint64(n), 0, false)
+
+	a := make([]fr.Element, domain.Cardinality)
+	b := make([]fr.Element, domain.Cardinality)
+	copy(a, p1)
+	copy(b, p2)
+	domain.FFT(a, fft.DIF, 0)
+	domain.FFT(b, fft.DIF, 0)
+	for i := 0; i < len(a); i++ {
+		a[i].Mul(&a[i], &b[i])
+	}
+	domain.FFTInverse(a, fft.DIT, 0)
+
+	return a[:n]
+}
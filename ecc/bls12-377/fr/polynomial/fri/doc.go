@@ -0,0 +1,23 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package fri provides a FRI (Fast Reed-Solomon Interactive oracle proof of proximity)
+// polynomial commitment scheme. It is transparent and only relies on hash functions.
+//
+// Polynomials are committed to through the Merkle root of their Reed-Solomon encoding,
+// and opened by proving that the quotients (f(X) - f(z))/(X - z) are close to
+// low degree polynomials.
+package fri
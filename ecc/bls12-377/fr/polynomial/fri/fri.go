@@ -0,0 +1,877 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	bls12377_pol "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidNbDigests              = errors.New("number of digests is not the same as the number of polynomials")
+	ErrInvalidNbPoints               = errors.New("number of points is not the same as the number of polynomials")
+	ErrInvalidPolynomialSize         = errors.New("invalid polynomial size (larger than the scheme size or == 0)")
+	ErrInvalidSize                   = errors.New("the size of the scheme must be at least 2")
+	ErrInvalidBlowupFactor           = errors.New("the blowup factor must be a power of 2, at least 2")
+	ErrInvalidNbQueries              = errors.New("the number of queries must be positive")
+	ErrInvalidType                   = errors.New("the arguments do not have the types expected by the FRI scheme")
+	ErrPointInDomain                 = errors.New("the opening point belongs to the evaluation domain")
+	ErrVerifyOpeningProof            = errors.New("can't verify opening proof")
+	ErrVerifyBatchOpeningSinglePoint = errors.New("can't verify batch opening proof at single point")
+	ErrVerifyBatchOpeningMultiPoints = errors.New("can't verify batch opening proof at multiple points")
+)
+
+// Digest commitment of a polynomial: root of the Merkle tree of its Reed-Solomon encoding.
+type Digest [sha256.Size]byte
+
+// Scheme stores FRI data.
+//
+// A polynomial of size at most n is encoded by its evaluations on the subgroup D of
+// size N = n*blowupFactor, that is on the blowupFactor cosets of the subgroup of size n
+// of Domain. The leaves of the Merkle tree committing to the encoding are the pairs
+// (f(x), f(-x)), x in D, sorted by x = w**i, i < N/2, w being the generator of D.
+type Scheme struct {
+
+	// Domain of size n, with the cosets used for the Reed-Solomon encoding
+	Domain *fft.Domain
+
+	// NbQueries number of queries of the proofs of proximity
+	NbQueries int
+}
+
+// MerkleProof opening of a leaf of a Merkle tree.
+type MerkleProof struct {
+
+	// Values evaluations at x and -x stored in the leaf
+	Values [2]fr.Element
+
+	// Path hashes needed to recompute the root, from the leaf to the root
+	Path []Digest
+}
+
+// ProofOfProximity FRI proof that evaluations on D are close to a polynomial of size at most n.
+//
+// The evaluations f_0 (never committed, the verifier computes them from the openings
+// of the polynomials) are folded log(n) times: with beta_j a challenge,
+// f_{j+1}(x**2) = (f_j(x) + f_j(-x))/2 + beta_j*(f_j(x) - f_j(-x))/(2x),
+// so that the last folded evaluations are the constant Final.
+type ProofOfProximity struct {
+
+	// Roots of the Merkle trees of the folded layers f_1, ..., f_{log(n)-1}
+	Roots []Digest
+
+	// Queries for each query, openings of the folded layers
+	Queries [][]MerkleProof
+
+	// Final value of the last folded layer
+	Final fr.Element
+}
+
+// Proof FRI proof for opening at a single point.
+//
+// The proof of proximity is run on X*(f(X) - f(z))/(X - z), which has a degree less than n
+// if and only if f has a degree less than n.
+type Proof struct {
+
+	// Point at which the polynomial is evaluated
+	Point fr.Element
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+
+	// Openings for each query, opening of the committed polynomial
+	Openings []MerkleProof
+
+	// ProofOfProximity proof that the quotient is a polynomial
+	ProofOfProximity ProofOfProximity
+}
+
+// BatchProofsSinglePoint opening proof for many polynomials at the same point.
+//
+// The proof of proximity is run on X*Sum_i gamma**i*(f_i(X) - f_i(z))/(X - z).
+type BatchProofsSinglePoint struct {
+
+	// Point at which the polynomials are evaluated
+	Point fr.Element
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+
+	// Openings for each query, openings of the committed polynomials
+	Openings [][]MerkleProof
+
+	// ProofOfProximity proof that the quotient is a polynomial
+	ProofOfProximity ProofOfProximity
+}
+
+// BatchProofsMultiPoints opening proof for many polynomials, each one
+// at its own point.
+//
+// The proof of proximity is run on X*Sum_i gamma**i*(f_i(X) - f_i(z_i))/(X - z_i).
+type BatchProofsMultiPoints struct {
+
+	// Points at which the polynomials are evaluated
+	Points []fr.Element
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+
+	// Openings for each query, openings of the committed polynomials
+	Openings [][]MerkleProof
+
+	// ProofOfProximity proof that the quotient is a polynomial
+	ProofOfProximity ProofOfProximity
+}
+
+// NewScheme returns a new FRI scheme for polynomials of size at most size (rounded up
+// to the next power of 2), with Reed-Solomon codes of rate 1/blowupFactor, and proofs
+// of proximity using nbQueries queries.
+//
+// Each query brings about log(blowupFactor) bits of security, for instance a blowup
+// factor of 4 with 64 queries gives a conjectured security of 128 bits.
+func NewScheme(size, blowupFactor uint64, nbQueries int) (*Scheme, error) {
+	if size < 2 {
+		return nil, ErrInvalidSize
+	}
+	if blowupFactor < 2 || blowupFactor&(blowupFactor-1) != 0 {
+		return nil, ErrInvalidBlowupFactor
+	}
+	if nbQueries <= 0 {
+		return nil, ErrInvalidNbQueries
+	}
+	depth := uint64(bits.TrailingZeros64(blowupFactor))
+	domain, err := fft.NewDomainE(size, depth, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Scheme{
+		Domain:    domain,
+		NbQueries: nbQueries,
+	}, nil
+}
+
+// Commit returns the root of the Merkle tree of the Reed-Solomon encoding of p.
+func (s *Scheme) Commit(p bls12377_pol.Polynomial) (Digest, error) {
+	evaluations, err := s.encode(p)
+	if err != nil {
+		return Digest{}, err
+	}
+	return merkleRoot(toLeaves(evaluations)), nil
+}
+
+// Open computes an opening proof of p at point.
+func (s *Scheme) Open(point *fr.Element, p bls12377_pol.Polynomial) (Proof, error) {
+	if len(p) == 0 || len(p) > int(s.Domain.Cardinality) {
+		return Proof{}, ErrInvalidPolynomialSize
+	}
+	res := Proof{
+		Point:        *point,
+		ClaimedValue: p.Evaluate(*point),
+	}
+
+	openings, pp, err := s.openQuotients(
+		[]fr.Element{res.Point},
+		[]fr.Element{res.ClaimedValue},
+		[]bls12377_pol.Polynomial{p},
+		true)
+	if err != nil {
+		return Proof{}, err
+	}
+
+	res.Openings = make([]MerkleProof, len(openings))
+	for i := 0; i < len(openings); i++ {
+		res.Openings[i] = openings[i][0]
+	}
+	res.ProofOfProximity = pp
+
+	return res, nil
+}
+
+// Verify verifies a FRI opening proof at a single point.
+func (s *Scheme) Verify(digest *Digest, proof *Proof) error {
+	openings := make([][]MerkleProof, len(proof.Openings))
+	for i := 0; i < len(openings); i++ {
+		openings[i] = proof.Openings[i : i+1]
+	}
+	return s.verifyQuotients(
+		[]fr.Element{proof.Point},
+		[]fr.Element{proof.ClaimedValue},
+		[]Digest{*digest},
+		openings,
+		&proof.ProofOfProximity,
+		true,
+		ErrVerifyOpeningProof)
+}
+
+// BatchOpenSinglePoint creates a batch opening proof at point of a list of polynomials.
+func (s *Scheme) BatchOpenSinglePoint(point *fr.Element, polynomials []bls12377_pol.Polynomial) (BatchProofsSinglePoint, error) {
+	if len(polynomials) == 0 {
+		return BatchProofsSinglePoint{}, ErrInvalidNbDigests
+	}
+
+	// compute the purported values
+	res := BatchProofsSinglePoint{Point: *point}
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	points := make([]fr.Element, len(polynomials))
+	for i := 0; i < len(polynomials); i++ {
+		if len(polynomials[i]) == 0 || len(polynomials[i]) > int(s.Domain.Cardinality) {
+			return BatchProofsSinglePoint{}, ErrInvalidPolynomialSize
+		}
+		res.ClaimedValues[i] = polynomials[i].Evaluate(*point)
+		points[i] = *point
+	}
+
+	var err error
+	res.Openings, res.ProofOfProximity, err = s.openQuotients(points, res.ClaimedValues, polynomials, true)
+	if err != nil {
+		return BatchProofsSinglePoint{}, err
+	}
+
+	return res, nil
+}
+
+// BatchVerifySinglePoint verifies a batched opening proof at a single point of a list of polynomials.
+func (s *Scheme) BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchProofsSinglePoint) error {
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	points := make([]fr.Element, nbDigests)
+	for i := 0; i < nbDigests; i++ {
+		points[i] = batchOpeningProof.Point
+	}
+	return s.verifyQuotients(
+		points,
+		batchOpeningProof.ClaimedValues,
+		digests,
+		batchOpeningProof.Openings,
+		&batchOpeningProof.ProofOfProximity,
+		true,
+		ErrVerifyBatchOpeningSinglePoint)
+}
+
+// BatchOpenMultiPoints creates a batch opening proof of a list of polynomials, the i-th polynomial
+// being opened at the i-th point.
+func (s *Scheme) BatchOpenMultiPoints(points []fr.Element, polynomials []bls12377_pol.Polynomial) (BatchProofsMultiPoints, error) {
+	if len(polynomials) == 0 {
+		return BatchProofsMultiPoints{}, ErrInvalidNbDigests
+	}
+	if len(points) != len(polynomials) {
+		return BatchProofsMultiPoints{}, ErrInvalidNbPoints
+	}
+
+	// compute the purported values
+	var res BatchProofsMultiPoints
+	res.Points = make([]fr.Element, len(points))
+	copy(res.Points, points)
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	for i := 0; i < len(polynomials); i++ {
+		if len(polynomials[i]) == 0 || len(polynomials[i]) > int(s.Domain.Cardinality) {
+			return BatchProofsMultiPoints{}, ErrInvalidPolynomialSize
+		}
+		res.ClaimedValues[i] = polynomials[i].Evaluate(points[i])
+	}
+
+	var err error
+	res.Openings, res.ProofOfProximity, err = s.openQuotients(res.Points, res.ClaimedValues, polynomials, false)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	return res, nil
+}
+
+// BatchVerifyMultiPoints verifies a batched opening proof of a list of polynomials at multiple points.
+func (s *Scheme) BatchVerifyMultiPoints(digests []Digest, batchOpeningProof *BatchProofsMultiPoints) error {
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	if len(batchOpeningProof.Points) != nbDigests {
+		return ErrInvalidNbPoints
+	}
+	return s.verifyQuotients(
+		batchOpeningProof.Points,
+		batchOpeningProof.ClaimedValues,
+		digests,
+		batchOpeningProof.Openings,
+		&batchOpeningProof.ProofOfProximity,
+		false,
+		ErrVerifyBatchOpeningMultiPoints)
+}
+
+// openQuotients computes the openings of the polynomials and the proof of proximity of
+// X*Q, Q = Sum_i gamma**i*(f_i(X) - values[i])/(X - points[i]).
+// If singlePoint is set, the points are all the same and bound only once to the challenges.
+func (s *Scheme) openQuotients(points, values []fr.Element, polynomials []bls12377_pol.Polynomial, singlePoint bool) ([][]MerkleProof, ProofOfProximity, error) {
+
+	// encode the polynomials
+	evaluations := make([][]fr.Element, len(polynomials))
+	for i := 0; i < len(polynomials); i++ {
+		var err error
+		evaluations[i], err = s.encode(polynomials[i])
+		if err != nil {
+			return nil, ProofOfProximity{}, err
+		}
+	}
+
+	return s.openEvaluations(points, values, evaluations, singlePoint)
+}
+
+// openEvaluations is openQuotients, the polynomials being given by their evaluations on D.
+func (s *Scheme) openEvaluations(points, values []fr.Element, evaluations [][]fr.Element, singlePoint bool) ([][]MerkleProof, ProofOfProximity, error) {
+
+	nbPolynomials := len(evaluations)
+	N := int(s.Domain.Cardinality << s.Domain.Depth)
+
+	trees := make([]*merkleTree, nbPolynomials)
+	digests := make([]Digest, nbPolynomials)
+	for i := 0; i < nbPolynomials; i++ {
+		trees[i] = newMerkleTree(toLeaves(evaluations[i]))
+		digests[i] = trees[i].root()
+	}
+
+	// derive gamma, binded to the digests, the points and the values
+	fs := s.newTranscript()
+	gamma, err := deriveGamma(&fs, digests, points, values, singlePoint)
+	if err != nil {
+		return nil, ProofOfProximity{}, err
+	}
+
+	// evaluations of the quotient on D
+	domainPoints := s.domainPoints()
+	q := make([]fr.Element, N)
+	var gammaI fr.Element
+	gammaI.SetOne()
+	for i := 0; i < nbPolynomials; i++ {
+		den := make([]fr.Element, N)
+		for k := 0; k < N; k++ {
+			den[k].Sub(&domainPoints[k], &points[i])
+			if den[k].IsZero() {
+				return nil, ProofOfProximity{}, ErrPointInDomain
+			}
+		}
+		den = bls12377_pol.BatchInvert(den)
+		parallel.Execute(N, func(start, end int) {
+			var t fr.Element
+			for k := start; k < end; k++ {
+				t.Sub(&evaluations[i][k], &values[i]).Mul(&t, &den[k]).Mul(&t, &gammaI)
+				q[k].Add(&q[k], &t)
+			}
+		})
+		gammaI.Mul(&gammaI, &gamma)
+	}
+
+	// Q has a degree less than n-1, enforced by the proof of proximity of X*Q to the polynomials
+	// of degree less than n. Without this correction, a polynomial f of degree n would pass.
+	parallel.Execute(N, func(start, end int) {
+		for k := start; k < end; k++ {
+			q[k].Mul(&q[k], &domainPoints[k])
+		}
+	})
+
+	pp, queries, err := s.proveProximity(&fs, q)
+	if err != nil {
+		return nil, ProofOfProximity{}, err
+	}
+
+	// openings of the polynomials at the queried leaves
+	openings := make([][]MerkleProof, len(queries))
+	for j := 0; j < len(queries); j++ {
+		openings[j] = make([]MerkleProof, nbPolynomials)
+		for i := 0; i < nbPolynomials; i++ {
+			openings[j][i] = trees[i].open(evaluations[i], queries[j])
+		}
+	}
+
+	return openings, pp, nil
+}
+
+// verifyQuotients verifies the openings of the polynomials and the proof of proximity of
+// X*Q, Q = Sum_i gamma**i*(f_i(X) - values[i])/(X - points[i]).
+func (s *Scheme) verifyQuotients(points, values []fr.Element, digests []Digest, openings [][]MerkleProof, pp *ProofOfProximity, singlePoint bool, errVerify error) error {
+
+	nbPolynomials := len(digests)
+	N := s.Domain.Cardinality << s.Domain.Depth
+
+	// shape of the openings
+	if len(openings) != s.NbQueries {
+		return errVerify
+	}
+	for j := 0; j < s.NbQueries; j++ {
+		if len(openings[j]) != nbPolynomials {
+			return errVerify
+		}
+	}
+
+	// derive the challenges
+	fs := s.newTranscript()
+	gamma, err := deriveGamma(&fs, digests, points, values, singlePoint)
+	if err != nil {
+		return err
+	}
+	betas, queries, err := s.deriveProximityChallenges(&fs, pp)
+	if err != nil {
+		return errVerify
+	}
+
+	gammai := make([]fr.Element, nbPolynomials)
+	gammai[0].SetOne()
+	for i := 1; i < nbPolynomials; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+
+	// values of X*Q at the queried leaves, computed from the openings
+	h := sha256.New()
+	leaves := make([][2]fr.Element, len(queries))
+	for j := 0; j < len(queries); j++ {
+		for i := 0; i < nbPolynomials; i++ {
+			if !verifyMerkleProof(h, digests[i], &openings[j][i], uint64(queries[j]), N/2) {
+				return errVerify
+			}
+		}
+
+		var x, t fr.Element
+		x.Exp(s.Domain.FinerGenerator, big.NewInt(int64(queries[j])))
+		den := make([]fr.Element, 2*nbPolynomials)
+		for i := 0; i < nbPolynomials; i++ {
+			den[2*i].Sub(&x, &points[i])
+			den[2*i+1].Neg(&x).Sub(&den[2*i+1], &points[i])
+			if den[2*i].IsZero() || den[2*i+1].IsZero() {
+				return errVerify
+			}
+		}
+		den = bls12377_pol.BatchInvert(den)
+		for i := 0; i < nbPolynomials; i++ {
+			t.Sub(&openings[j][i].Values[0], &values[i]).Mul(&t, &den[2*i]).Mul(&t, &gammai[i])
+			leaves[j][0].Add(&leaves[j][0], &t)
+			t.Sub(&openings[j][i].Values[1], &values[i]).Mul(&t, &den[2*i+1]).Mul(&t, &gammai[i])
+			leaves[j][1].Add(&leaves[j][1], &t)
+		}
+		leaves[j][0].Mul(&leaves[j][0], &x)
+		leaves[j][1].Mul(&leaves[j][1], &x).Neg(&leaves[j][1])
+	}
+
+	if err := s.verifyProximity(pp, betas, queries, leaves); err != nil {
+		return errVerify
+	}
+
+	return nil
+}
+
+// proveProximity runs the commit phase and the query phase of FRI on the evaluations f on D,
+// using the transcript fs. It returns the proof of proximity, and the queried leaves of the
+// first layer, which are not committed by the proof of proximity.
+//
+// The evaluations are folded log(n) times, and the folded layers but the last one are committed.
+// The leaf at index k of the first layer is queried at index k mod (N/4) in the second layer, and so on.
+func (s *Scheme) proveProximity(fs *fiatshamir.Transcript, f []fr.Element) (ProofOfProximity, []int, error) {
+
+	nbRounds := bits.TrailingZeros64(s.Domain.Cardinality)
+	domainPoints := s.domainPoints()
+
+	// commit phase: fold the evaluations, and commit to the folded layers
+	var pp ProofOfProximity
+	pp.Roots = make([]Digest, nbRounds-1)
+	layers := make([]*merkleTree, nbRounds-1)
+	for j := 0; j < nbRounds; j++ {
+		if j > 0 {
+			layers[j-1] = newMerkleTree(toLeaves(f))
+			pp.Roots[j-1] = layers[j-1].root()
+		}
+		beta, err := deriveBeta(fs, j, pp.Roots)
+		if err != nil {
+			return ProofOfProximity{}, nil, err
+		}
+		f = fold(f, domainPoints, &beta)
+		domainPoints = squareDomain(domainPoints)
+	}
+	pp.Final = f[0]
+
+	// query phase
+	queries, err := s.deriveQueries(fs, &pp.Final)
+	if err != nil {
+		return ProofOfProximity{}, nil, err
+	}
+	pp.Queries = make([][]MerkleProof, len(queries))
+	for i := 0; i < len(queries); i++ {
+		pp.Queries[i] = make([]MerkleProof, nbRounds-1)
+		index := queries[i]
+		nbLeaves := len(f) << (nbRounds - 1)
+		for j := 0; j < nbRounds-1; j++ {
+			nbLeaves /= 2
+			index %= nbLeaves
+			pp.Queries[i][j] = layers[j].open(nil, index)
+		}
+	}
+
+	return pp, queries, nil
+}
+
+// deriveProximityChallenges derives the folding challenges and the queries of the proof of
+// proximity, from a transcript fs in the state it had when the prover called proveProximity.
+func (s *Scheme) deriveProximityChallenges(fs *fiatshamir.Transcript, pp *ProofOfProximity) ([]fr.Element, []int, error) {
+	nbRounds := bits.TrailingZeros64(s.Domain.Cardinality)
+	if len(pp.Roots) != nbRounds-1 {
+		return nil, nil, ErrVerifyOpeningProof
+	}
+	betas := make([]fr.Element, nbRounds)
+	for j := 0; j < nbRounds; j++ {
+		var err error
+		if betas[j], err = deriveBeta(fs, j, pp.Roots); err != nil {
+			return nil, nil, err
+		}
+	}
+	queries, err := s.deriveQueries(fs, &pp.Final)
+	if err != nil {
+		return nil, nil, err
+	}
+	return betas, queries, nil
+}
+
+// verifyProximity checks the proof of proximity, leaves being the values (f(x), f(-x))
+// of the first layer at the queried leaves.
+func (s *Scheme) verifyProximity(pp *ProofOfProximity, betas []fr.Element, queries []int, leaves [][2]fr.Element) error {
+
+	nbRounds := bits.TrailingZeros64(s.Domain.Cardinality)
+
+	// shape of the proof
+	if len(pp.Roots) != nbRounds-1 || len(pp.Queries) != len(queries) || len(leaves) != len(queries) {
+		return ErrVerifyOpeningProof
+	}
+	for i := 0; i < len(queries); i++ {
+		if len(pp.Queries[i]) != nbRounds-1 {
+			return ErrVerifyOpeningProof
+		}
+	}
+
+	// generators of the successive domains
+	generators := make([]fr.Element, nbRounds)
+	generators[0] = s.Domain.FinerGenerator
+	for j := 1; j < nbRounds; j++ {
+		generators[j].Square(&generators[j-1])
+	}
+
+	h := sha256.New()
+	for i := 0; i < len(queries); i++ {
+		index := uint64(queries[i])
+		nbLeaves := s.Domain.Cardinality << (s.Domain.Depth - 1)
+		a, b := leaves[i][0], leaves[i][1]
+
+		for j := 0; j < nbRounds; j++ {
+			var xInv fr.Element
+			xInv.Exp(generators[j], new(big.Int).SetUint64(index)).Inverse(&xInv)
+			folded := foldPair(&a, &b, &xInv, &betas[j])
+
+			// the last folded layer is constant
+			if j == nbRounds-1 {
+				if !folded.Equal(&pp.Final) {
+					return ErrVerifyOpeningProof
+				}
+				break
+			}
+
+			// the folded value is at position index in the next layer,
+			// that is in the leaf index mod nbLeaves
+			nbLeaves /= 2
+			opening := &pp.Queries[i][j]
+			if !verifyMerkleProof(h, pp.Roots[j], opening, index%nbLeaves, nbLeaves) {
+				return ErrVerifyOpeningProof
+			}
+			if !folded.Equal(&opening.Values[index/nbLeaves]) {
+				return ErrVerifyOpeningProof
+			}
+			a, b = opening.Values[0], opening.Values[1]
+			index %= nbLeaves
+		}
+	}
+
+	return nil
+}
+
+// encode returns the evaluations of p on D, in natural order.
+//
+// p is evaluated on the cosets of the subgroup of size n with an FFT per coset.
+// The j-th element of the c-th coset is w**(c + j*blowupFactor), w being the generator of D.
+func (s *Scheme) encode(p bls12377_pol.Polynomial) ([]fr.Element, error) {
+	n := int(s.Domain.Cardinality)
+	if len(p) == 0 || len(p) > n {
+		return nil, ErrInvalidPolynomialSize
+	}
+	blowupFactor := 1 << s.Domain.Depth
+
+	res := make([]fr.Element, n*blowupFactor)
+	buf := make([]fr.Element, n)
+	for c := 0; c < blowupFactor; c++ {
+		copy(buf, p)
+		for j := len(p); j < n; j++ {
+			buf[j].SetZero()
+		}
+		s.Domain.FFT(buf, fft.DIF, uint64(c))
+		fft.BitReverse(buf)
+		for j := 0; j < n; j++ {
+			res[c+j*blowupFactor] = buf[j]
+		}
+	}
+
+	return res, nil
+}
+
+// domainPoints returns the elements of D, in natural order
+func (s *Scheme) domainPoints() []fr.Element {
+	N := int(s.Domain.Cardinality << s.Domain.Depth)
+	res := make([]fr.Element, N)
+	res[0].SetOne()
+	for i := 1; i < N; i++ {
+		res[i].Mul(&res[i-1], &s.Domain.FinerGenerator)
+	}
+	return res
+}
+
+// squareDomain returns the squares of the first half of the domain, that is the next domain
+func squareDomain(domain []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(domain)/2)
+	for i := 0; i < len(res); i++ {
+		res[i].Square(&domain[i])
+	}
+	return res
+}
+
+// fold folds the evaluations f on domain, with the challenge beta:
+// res(x**2) = (f(x) + f(-x))/2 + beta*(f(x) - f(-x))/(2x)
+func fold(f, domain []fr.Element, beta *fr.Element) []fr.Element {
+	m := len(f) / 2
+	xInv := bls12377_pol.BatchInvert(domain[:m])
+	res := make([]fr.Element, m)
+	parallel.Execute(m, func(start, end int) {
+		for k := start; k < end; k++ {
+			res[k] = foldPair(&f[k], &f[k+m], &xInv[k], beta)
+		}
+	})
+	return res
+}
+
+// foldPair returns (a + b)/2 + beta*(a - b)/(2x), a and b being the evaluations at x and -x
+func foldPair(a, b, xInv, beta *fr.Element) fr.Element {
+	var res, t, twoInv fr.Element
+	twoInv.SetUint64(2).Inverse(&twoInv)
+	t.Sub(a, b).Mul(&t, xInv).Mul(&t, beta)
+	res.Add(a, b).Add(&res, &t).Mul(&res, &twoInv)
+	return res
+}
+
+// toLeaves returns the leaves (f(x), f(-x)) of the Merkle tree committing to the evaluations f
+func toLeaves(f []fr.Element) [][]byte {
+	m := len(f) / 2
+	res := make([][]byte, m)
+	for k := 0; k < m; k++ {
+		res[k] = make([]byte, 0, 2*fr.Bytes)
+		b := f[k].Bytes()
+		res[k] = append(res[k], b[:]...)
+		b = f[k+m].Bytes()
+		res[k] = append(res[k], b[:]...)
+	}
+	return res
+}
+
+// merkleRoot returns the root of the Merkle tree of the leaves
+func merkleRoot(leaves [][]byte) Digest {
+	tree := merkletree.New(sha256.New())
+	for i := 0; i < len(leaves); i++ {
+		tree.Push(leaves[i])
+	}
+	var res Digest
+	copy(res[:], tree.Root())
+	return res
+}
+
+// merkleTree Merkle tree of a list of leaves, with all its nodes cached so that
+// several leaves can be opened without rebuilding the tree.
+type merkleTree struct {
+	leaves [][]byte
+
+	// nodes[h][i] is the root of the i-th subtree of height h
+	nodes [][][]byte
+}
+
+// newMerkleTree builds the Merkle tree of the leaves, whose number must be a power of 2
+func newMerkleTree(leaves [][]byte) *merkleTree {
+	h := sha256.New()
+	height := bits.TrailingZeros(uint(len(leaves)))
+	res := &merkleTree{leaves: leaves, nodes: make([][][]byte, height+1)}
+
+	res.nodes[0] = make([][]byte, len(leaves))
+	for i := 0; i < len(leaves); i++ {
+		tree := merkletree.New(h)
+		tree.Push(leaves[i])
+		res.nodes[0][i] = tree.Root()
+	}
+	for k := 1; k <= height; k++ {
+		res.nodes[k] = make([][]byte, len(res.nodes[k-1])/2)
+		for i := 0; i < len(res.nodes[k]); i++ {
+			tree := merkletree.New(h)
+			if err := tree.PushSubTree(k-1, res.nodes[k-1][2*i]); err != nil {
+				panic(err) // can't happen, the subtrees are balanced
+			}
+			if err := tree.PushSubTree(k-1, res.nodes[k-1][2*i+1]); err != nil {
+				panic(err)
+			}
+			res.nodes[k][i] = tree.Root()
+		}
+	}
+
+	return res
+}
+
+// root returns the root of the tree
+func (t *merkleTree) root() Digest {
+	var res Digest
+	copy(res[:], t.nodes[len(t.nodes)-1][0])
+	return res
+}
+
+// Open returns the opening of the leaf at index. If f is nil,
+// the values are read from the leaf.
+func (t *merkleTree) open(f []fr.Element, index int) MerkleProof {
+	var res MerkleProof
+	res.Path = make([]Digest, len(t.nodes)-1)
+	for k := 0; k < len(res.Path); k++ {
+		copy(res.Path[k][:], t.nodes[k][(index>>k)^1])
+	}
+	if f != nil {
+		res.Values[0] = f[index]
+		res.Values[1] = f[index+len(f)/2]
+	} else {
+		res.Values[0].SetBytes(t.leaves[index][:fr.Bytes])
+		res.Values[1].SetBytes(t.leaves[index][fr.Bytes:])
+	}
+	return res
+}
+
+// verifyMerkleProof checks that the opening is the leaf at index of the Merkle tree with the given root
+func verifyMerkleProof(h hash.Hash, root Digest, opening *MerkleProof, index, numLeaves uint64) bool {
+	proofSet := make([][]byte, len(opening.Path)+1)
+	proofSet[0] = toLeaves(opening.Values[:])[0]
+	for i := 0; i < len(opening.Path); i++ {
+		proofSet[i+1] = opening.Path[i][:]
+	}
+	return merkletree.VerifyProof(h, root[:], proofSet, index, numLeaves)
+}
+
+// newTranscript returns the transcript used to derive the challenges: gamma, the
+// challenges of the folding rounds, and the seed of the queries.
+func (s *Scheme) newTranscript() fiatshamir.Transcript {
+	nbRounds := bits.TrailingZeros64(s.Domain.Cardinality)
+	challenges := make([]string, 0, nbRounds+2)
+	challenges = append(challenges, "gamma")
+	for j := 0; j < nbRounds; j++ {
+		challenges = append(challenges, challengeBeta(j))
+	}
+	challenges = append(challenges, "queries")
+	return fiatshamir.NewTranscript(fiatshamir.SHA256, challenges...)
+}
+
+// challengeBeta returns the name of the challenge of the j-th folding round
+func challengeBeta(j int) string {
+	return "beta" + strconv.Itoa(j)
+}
+
+// deriveGamma derives the challenge gamma used to combine the quotients,
+// binded to the digests, the points and the claimed values.
+func deriveGamma(fs *fiatshamir.Transcript, digests []Digest, points, values []fr.Element, singlePoint bool) (fr.Element, error) {
+	for i := 0; i < len(digests); i++ {
+		if err := fs.Bind("gamma", digests[i][:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	if singlePoint {
+		points = points[:1]
+	}
+	for i := 0; i < len(points); i++ {
+		b := points[i].Bytes()
+		if err := fs.Bind("gamma", b[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := 0; i < len(values); i++ {
+		b := values[i].Bytes()
+		if err := fs.Bind("gamma", b[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	return computeChallenge(fs, "gamma")
+}
+
+// deriveBeta derives the challenge of the j-th folding round, binded to the
+// root of the j-th layer (the first layer is bound through gamma).
+func deriveBeta(fs *fiatshamir.Transcript, j int, roots []Digest) (fr.Element, error) {
+	name := challengeBeta(j)
+	if j > 0 {
+		if err := fs.Bind(name, roots[j-1][:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	return computeChallenge(fs, name)
+}
+
+// deriveQueries derives the indexes of the queries, in [0, N/2), binded to the final value
+func (s *Scheme) deriveQueries(fs *fiatshamir.Transcript, final *fr.Element) ([]int, error) {
+	b := final.Bytes()
+	if err := fs.Bind("queries", b[:]); err != nil {
+		return nil, err
+	}
+	seed, err := fs.ComputeChallenge("queries")
+	if err != nil {
+		return nil, err
+	}
+
+	nbLeaves := s.Domain.Cardinality << (s.Domain.Depth - 1)
+	res := make([]int, s.NbQueries)
+	h := sha256.New()
+	var counter [8]byte
+	for q := 0; q < s.NbQueries; q++ {
+		h.Reset()
+		binary.BigEndian.PutUint64(counter[:], uint64(q))
+		h.Write(seed)
+		h.Write(counter[:])
+		res[q] = int(binary.BigEndian.Uint64(h.Sum(nil)[:8]) % nbLeaves)
+	}
+	return res, nil
+}
+
+// computeChallenge computes the challenge name and converts it to a fr.Element
+func computeChallenge(fs *fiatshamir.Transcript, name string) (fr.Element, error) {
+	b, err := fs.ComputeChallenge(name)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(b)
+	return res, nil
+}
@@ -0,0 +1,740 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	bls12377_pol "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/polynomial"
+)
+
+// testScheme FRI scheme for polynomials of size 64, with a blowup factor of 4
+var testScheme *Scheme
+
+func init() {
+	const size = 64
+	var err error
+	testScheme, err = NewScheme(size, 4, 16)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func randomPolynomial(size int) bls12377_pol.Polynomial {
+	f := make(bls12377_pol.Polynomial, size)
+	for i := 0; i < size; i++ {
+		f[i].SetRandom()
+	}
+	return f
+}
+
+// commitAll commits to each polynomial of f
+func commitAll(s *Scheme, f []bls12377_pol.Polynomial) ([]Digest, error) {
+	digests := make([]Digest, len(f))
+	for i := 0; i < len(f); i++ {
+		var err error
+		digests[i], err = s.Commit(f[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return digests, nil
+}
+
+func TestNewScheme(t *testing.T) {
+
+	if _, err := NewScheme(1, 4, 16); err != ErrInvalidSize {
+		t.Fatal("a scheme of size 1 should be rejected")
+	}
+	if _, err := NewScheme(64, 3, 16); err != ErrInvalidBlowupFactor {
+		t.Fatal("a blowup factor which is not a power of 2 should be rejected")
+	}
+	if _, err := NewScheme(64, 1, 16); err != ErrInvalidBlowupFactor {
+		t.Fatal("a blowup factor of 1 should be rejected")
+	}
+	if _, err := NewScheme(64, 4, 0); err != ErrInvalidNbQueries {
+		t.Fatal("a proof without queries should be rejected")
+	}
+	if _, err := NewScheme(64, 1<<62, 16); err != fft.ErrCosetsTooLarge {
+		t.Fatal("a blowup factor too large for the 2-adic subgroups of fr should be rejected")
+	}
+
+	// the size is rounded up to the next power of 2
+	s, err := NewScheme(33, 2, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Domain.Cardinality != 64 || s.Domain.Depth != 1 {
+		t.Fatal("wrong domain")
+	}
+}
+
+func TestSerializationScheme(t *testing.T) {
+
+	// serialize the scheme...
+	var buf bytes.Buffer
+	written, err := testScheme.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reconstruct the scheme
+	var _scheme Scheme
+	read, err := _scheme.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != read {
+		t.Fatal("number of bytes written and read differ")
+	}
+
+	// compare
+	if !reflect.DeepEqual(testScheme, &_scheme) {
+		t.Fatal("scheme serialization failed")
+	}
+
+}
+
+func TestEncode(t *testing.T) {
+
+	// create a polynomial
+	f := randomPolynomial(60)
+
+	// the encoding is the evaluation of f on D, in natural order
+	evaluations, err := testScheme.encode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := testScheme.domainPoints()
+	if len(evaluations) != 4*64 || len(points) != len(evaluations) {
+		t.Fatal("wrong size of the encoding")
+	}
+	for i := 0; i < len(points); i++ {
+		expected := f.Evaluate(points[i])
+		if !evaluations[i].Equal(&expected) {
+			t.Fatal("wrong Reed-Solomon encoding")
+		}
+	}
+
+	// the second half of D is the opposite of the first half
+	for i := 0; i < len(points)/2; i++ {
+		var minusX fr.Element
+		minusX.Neg(&points[i])
+		if !minusX.Equal(&points[i+len(points)/2]) {
+			t.Fatal("the leaves should store (f(x), f(-x))")
+		}
+	}
+
+}
+
+func TestCommitInvalidSize(t *testing.T) {
+
+	// a polynomial larger than the scheme size cannot be committed to
+	f := randomPolynomial(int(testScheme.Domain.Cardinality) + 1)
+	if _, err := testScheme.Commit(f); err != ErrInvalidPolynomialSize {
+		t.Fatal("commitment to a polynomial larger than the scheme size should fail")
+	}
+	if _, err := testScheme.Commit(nil); err != ErrInvalidPolynomialSize {
+		t.Fatal("commitment to an empty polynomial should fail")
+	}
+
+}
+
+func TestVerifySinglePoint(t *testing.T) {
+
+	// create a polynomial
+	f := randomPolynomial(60)
+
+	// commit the polynomial
+	digest, err := testScheme.Commit(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compute opening proof at a random point
+	var point fr.Element
+	point.SetString("4321")
+	proof, err := testScheme.Open(&point, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed valued
+	expected := f.Evaluate(point)
+	if !proof.ClaimedValue.Equal(&expected) {
+		t.Fatal("inconsistant claimed value")
+	}
+
+	// the proof has one query per layer but the last one
+	if len(proof.Openings) != 16 || len(proof.ProofOfProximity.Roots) != 5 {
+		t.Fatal("wrong shape of the proof")
+	}
+
+	// verify correct proof
+	err = testScheme.Verify(&digest, &proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify wrong proof
+	proof.ClaimedValue.Double(&proof.ClaimedValue)
+	err = testScheme.Verify(&digest, &proof)
+	if err == nil {
+		t.Fatal("verifying wrong proof should have failed")
+	}
+	proof.ClaimedValue.Set(&expected)
+
+	// verify proof with a tampered opening
+	proof.Openings[3].Values[0], proof.Openings[3].Values[1] = proof.Openings[3].Values[1], proof.Openings[3].Values[0]
+	err = testScheme.Verify(&digest, &proof)
+	if err == nil {
+		t.Fatal("verifying proof with tampered openings should have failed")
+	}
+	proof.Openings[3].Values[0], proof.Openings[3].Values[1] = proof.Openings[3].Values[1], proof.Openings[3].Values[0]
+
+	// verify proof with a tampered final value
+	var final fr.Element
+	final.Set(&proof.ProofOfProximity.Final)
+	proof.ProofOfProximity.Final.Double(&proof.ProofOfProximity.Final)
+	err = testScheme.Verify(&digest, &proof)
+	if err == nil {
+		t.Fatal("verifying proof with tampered final value should have failed")
+	}
+	proof.ProofOfProximity.Final.Set(&final)
+
+	// verify proof at another point
+	proof.Point.SetString("1234")
+	err = testScheme.Verify(&digest, &proof)
+	if err == nil {
+		t.Fatal("verifying proof at another point should have failed")
+	}
+}
+
+func TestVerifySinglePointConstant(t *testing.T) {
+
+	f := randomPolynomial(1)
+	digest, err := testScheme.Commit(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var point fr.Element
+	point.SetRandom()
+	proof, err := testScheme.Open(&point, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := testScheme.Verify(&digest, &proof); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenPointInDomain(t *testing.T) {
+
+	f := randomPolynomial(60)
+
+	// the quotient is not defined on D
+	point := testScheme.Domain.FinerGenerator
+	if _, err := testScheme.Open(&point, f); err != ErrPointInDomain {
+		t.Fatal("opening at a point of the evaluation domain should fail")
+	}
+}
+
+func TestVerifyDegreeBound(t *testing.T) {
+
+	// opening proof of the polynomial f, committed to with its evaluations on D
+	// computed one by one, so that f can be larger than what Commit accepts
+	open := func(f bls12377_pol.Polynomial) error {
+		domainPoints := testScheme.domainPoints()
+		evaluations := make([]fr.Element, len(domainPoints))
+		for k := 0; k < len(domainPoints); k++ {
+			evaluations[k] = f.Evaluate(domainPoints[k])
+		}
+		digest := merkleRoot(toLeaves(evaluations))
+
+		var proof Proof
+		proof.Point.SetRandom()
+		proof.ClaimedValue = f.Evaluate(proof.Point)
+		openings, pp, err := testScheme.openEvaluations(
+			[]fr.Element{proof.Point},
+			[]fr.Element{proof.ClaimedValue},
+			[][]fr.Element{evaluations},
+			true)
+		if err != nil {
+			return err
+		}
+		proof.Openings = make([]MerkleProof, len(openings))
+		for i := 0; i < len(openings); i++ {
+			proof.Openings[i] = openings[i][0]
+		}
+		proof.ProofOfProximity = pp
+
+		return testScheme.Verify(&digest, &proof)
+	}
+
+	// a polynomial of degree n-1 is accepted
+	n := int(testScheme.Domain.Cardinality)
+	if err := open(randomPolynomial(n)); err != nil {
+		t.Fatal(err)
+	}
+
+	// a polynomial of degree n is rejected
+	if err := open(randomPolynomial(n + 1)); err != ErrVerifyOpeningProof {
+		t.Fatal("opening a polynomial of degree n should have failed")
+	}
+}
+
+func TestProximity(t *testing.T) {
+
+	// same evaluation domain D as testScheme, for polynomials of size 32
+	s, err := NewScheme(32, 8, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Domain.FinerGenerator.Equal(&testScheme.Domain.FinerGenerator) {
+		t.Fatal("the evaluation domains should be the same")
+	}
+
+	prove := func(p bls12377_pol.Polynomial) error {
+		evaluations, err := testScheme.encode(p)
+		if err != nil {
+			return err
+		}
+
+		// the challenges of the proof of proximity are derived after gamma
+		transcript := func() (fiatshamir.Transcript, error) {
+			fs := s.newTranscript()
+			_, err := fs.ComputeChallenge("gamma")
+			return fs, err
+		}
+
+		fs, err := transcript()
+		if err != nil {
+			return err
+		}
+		pp, _, err := s.proveProximity(&fs, evaluations)
+		if err != nil {
+			return err
+		}
+
+		if fs, err = transcript(); err != nil {
+			return err
+		}
+		betas, queries, err := s.deriveProximityChallenges(&fs, &pp)
+		if err != nil {
+			return err
+		}
+		leaves := make([][2]fr.Element, len(queries))
+		for i := 0; i < len(queries); i++ {
+			leaves[i][0] = evaluations[queries[i]]
+			leaves[i][1] = evaluations[queries[i]+len(evaluations)/2]
+		}
+		return s.verifyProximity(&pp, betas, queries, leaves)
+	}
+
+	// the encoding of a polynomial of size 32 is accepted
+	if err := prove(randomPolynomial(32)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the encoding of a polynomial of size 64 is rejected
+	if err := prove(randomPolynomial(64)); err == nil {
+		t.Fatal("proof of proximity of a polynomial too large should have failed")
+	}
+}
+
+func TestBatchVerifySinglePoint(t *testing.T) {
+
+	// create polynomials
+	f := make([]bls12377_pol.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		f[i] = randomPolynomial(60 - i)
+	}
+
+	// commit the polynomials
+	digests, err := commitAll(testScheme, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compute opening proof at a random point
+	var point fr.Element
+	point.SetString("4321")
+	proof, err := testScheme.BatchOpenSinglePoint(&point, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed values
+	for i := 0; i < 10; i++ {
+		expectedClaim := f[i].Evaluate(point)
+		if !expectedClaim.Equal(&proof.ClaimedValues[i]) {
+			t.Fatal("inconsistant claimed values")
+		}
+	}
+
+	// verify correct proof
+	err = testScheme.BatchVerifySinglePoint(digests, &proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify wrong proof
+	var claimedValue fr.Element
+	claimedValue.Set(&proof.ClaimedValues[0])
+	proof.ClaimedValues[0].Double(&proof.ClaimedValues[0])
+	err = testScheme.BatchVerifySinglePoint(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying wrong proof should have failed")
+	}
+	proof.ClaimedValues[0].Set(&claimedValue)
+
+	// verify the proof against another set of digests
+	digests[0], digests[1] = digests[1], digests[0]
+	err = testScheme.BatchVerifySinglePoint(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying proof with swapped digests should have failed")
+	}
+
+}
+
+func TestBatchVerifyMultiPoints(t *testing.T) {
+
+	// create polynomials
+	f := make([]bls12377_pol.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		f[i] = randomPolynomial(60 - i)
+	}
+
+	// commit the polynomials
+	digests, err := commitAll(testScheme, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// pick the points, PLONK style: the first polynomials are opened at zeta,
+	// the others at zeta*omega
+	var zeta, omega fr.Element
+	zeta.SetRandom()
+	omega.SetRandom()
+	points := make([]fr.Element, 10)
+	for i := 0; i < 10; i++ {
+		points[i].Set(&zeta)
+		if i >= 7 {
+			points[i].Mul(&points[i], &omega)
+		}
+	}
+
+	// compute the batch opening proof
+	proof, err := testScheme.BatchOpenMultiPoints(points, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed values
+	for i := 0; i < 10; i++ {
+		expectedClaim := f[i].Evaluate(points[i])
+		if !expectedClaim.Equal(&proof.ClaimedValues[i]) {
+			t.Fatal("inconsistant claimed values")
+		}
+	}
+
+	// verify correct proof
+	err = testScheme.BatchVerifyMultiPoints(digests, &proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify wrong proof
+	var claimedValue fr.Element
+	claimedValue.Set(&proof.ClaimedValues[8])
+	proof.ClaimedValues[8].Double(&proof.ClaimedValues[8])
+	err = testScheme.BatchVerifyMultiPoints(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying wrong proof should have failed")
+	}
+	proof.ClaimedValues[8].Set(&claimedValue)
+
+	// verify the proof at other points
+	proof.Points[0], proof.Points[9] = proof.Points[9], proof.Points[0]
+	err = testScheme.BatchVerifyMultiPoints(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying proof at swapped points should have failed")
+	}
+	proof.Points[0], proof.Points[9] = proof.Points[9], proof.Points[0]
+
+	// verify the proof against another set of digests
+	digests[0], digests[1] = digests[1], digests[0]
+	err = testScheme.BatchVerifyMultiPoints(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying proof with swapped digests should have failed")
+	}
+
+}
+
+func TestGenericScheme(t *testing.T) {
+
+	var s polynomial.CommitmentScheme = &GenericScheme{Scheme: testScheme}
+
+	// single point
+	f := randomPolynomial(60)
+	var point fr.Element
+	point.SetRandom()
+	digest := s.Commit(f)
+	proof := s.Open(&point, f)
+	if err := s.Verify(&point, digest, proof); err != nil {
+		t.Fatal(err)
+	}
+	var otherPoint fr.Element
+	otherPoint.Double(&point)
+	if err := s.Verify(&otherPoint, digest, proof); err == nil {
+		t.Fatal("verifying proof at another point should have failed")
+	}
+
+	// batch opening at a single point
+	polynomials := []polynomial.Polynomial{f, randomPolynomial(10), randomPolynomial(30)}
+	digests := make([]polynomial.Digest, len(polynomials))
+	for i := 0; i < len(polynomials); i++ {
+		digests[i] = s.Commit(polynomials[i])
+	}
+	batchProof := s.BatchOpenSinglePoint(&point, polynomials)
+	claimedValues := batchProof.(*BatchProofsSinglePoint).ClaimedValues
+	if err := s.BatchVerifySinglePoint(&point, claimedValues, digests, batchProof); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchVerifySinglePoint(&otherPoint, claimedValues, digests, batchProof); err == nil {
+		t.Fatal("verifying batch proof at another point should have failed")
+	}
+
+	// batch opening at multiple points
+	points := []fr.Element{point, otherPoint, point}
+	multiPointsProof := s.BatchOpenMultiPoints(points, polynomials)
+	claimedValues = multiPointsProof.(*BatchProofsMultiPoints).ClaimedValues
+	if err := s.BatchVerifyMultiPoints(points, claimedValues, digests, multiPointsProof); err != nil {
+		t.Fatal(err)
+	}
+	points[0], points[1] = points[1], points[0]
+	if err := s.BatchVerifyMultiPoints(points, claimedValues, digests, multiPointsProof); err == nil {
+		t.Fatal("verifying batch proof at other points should have failed")
+	}
+
+	// arguments of the wrong type
+	if err := s.Verify(point, digest, proof); err != ErrInvalidType {
+		t.Fatal("a point which is not a *fr.Element should be rejected")
+	}
+}
+
+func TestSerializationProofs(t *testing.T) {
+
+	f := randomPolynomial(60)
+	var point fr.Element
+	point.SetRandom()
+
+	// single point opening proof
+	proof, err := testScheme.Open(&point, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _proof Proof
+	if _, err := _proof.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(proof, _proof) {
+		t.Fatal("opening proof serialization failed")
+	}
+
+	// batch opening proof
+	polynomials := []bls12377_pol.Polynomial{f, randomPolynomial(10)}
+	batchProof, err := testScheme.BatchOpenSinglePoint(&point, polynomials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := batchProof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _batchProof BatchProofsSinglePoint
+	if _, err := _batchProof.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(batchProof, _batchProof) {
+		t.Fatal("batch opening proof serialization failed")
+	}
+
+	// multi points batch opening proof
+	var otherPoint fr.Element
+	otherPoint.SetRandom()
+	points := []fr.Element{point, otherPoint}
+	multiPointsProof, err := testScheme.BatchOpenMultiPoints(points, polynomials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := multiPointsProof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _multiPointsProof BatchProofsMultiPoints
+	if _, err := _multiPointsProof.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(multiPointsProof, _multiPointsProof) {
+		t.Fatal("multi points batch opening proof serialization failed")
+	}
+
+	// digest
+	digest, err := testScheme.Commit(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := digest.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _digest Digest
+	if _, err := _digest.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if digest != _digest {
+		t.Fatal("digest serialization failed")
+	}
+}
+
+const benchSize = 1 << 12
+
+func BenchmarkFRICommit(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize, 4, 64)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// random polynomial
+	p := randomPolynomial(benchSize / 2)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchScheme.Commit(p)
+	}
+}
+
+func BenchmarkFRIOpen(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize, 4, 64)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// random polynomial
+	p := randomPolynomial(benchSize / 2)
+	var r fr.Element
+	r.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchScheme.Open(&r, p)
+	}
+}
+
+func BenchmarkFRIVerify(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize, 4, 64)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// random polynomial
+	p := randomPolynomial(benchSize / 2)
+	var r fr.Element
+	r.SetRandom()
+
+	// commit
+	comm, err := benchScheme.Commit(p)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// open
+	openingProof, err := benchScheme.Open(&r, p)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchScheme.Verify(&comm, &openingProof)
+	}
+}
+
+func BenchmarkFRIBatchOpen10(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize, 4, 64)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// 10 random polynomials
+	ps := make([]bls12377_pol.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		ps[i] = randomPolynomial(benchSize / 2)
+	}
+
+	var r fr.Element
+	r.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchScheme.BatchOpenSinglePoint(&r, ps)
+	}
+}
+
+func BenchmarkFRIBatchVerify10(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize, 4, 64)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// 10 random polynomials
+	ps := make([]bls12377_pol.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		ps[i] = randomPolynomial(benchSize / 2)
+	}
+
+	// commitments
+	commitments, err := commitAll(benchScheme, ps)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var r fr.Element
+	r.SetRandom()
+	proof, err := benchScheme.BatchOpenSinglePoint(&r, ps)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchScheme.BatchVerifySinglePoint(commitments, &proof)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+)
+
+// maxSliceLength bounds the lengths read from a binary encoding, to avoid
+// allocating huge slices when decoding malformed data
+const maxSliceLength = 1 << 20
+
+var errInvalidLength = errors.New("invalid slice length")
+
+// WriteTo writes binary encoding of the scheme data: the domain, followed
+// by the number of queries.
+func (s *Scheme) WriteTo(w io.Writer) (int64, error) {
+	n, err := s.Domain.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	enc := newEncoder(w)
+	enc.writeUint64(uint64(s.NbQueries))
+	return n + enc.n, enc.err
+}
+
+// ReadFrom decodes scheme data.
+func (s *Scheme) ReadFrom(r io.Reader) (int64, error) {
+	s.Domain = &fft.Domain{}
+	n, err := s.Domain.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	dec := newDecoder(r)
+	s.NbQueries = int(dec.readUint64())
+	if dec.err == nil && (s.NbQueries <= 0 || s.NbQueries > maxSliceLength) {
+		dec.err = ErrInvalidNbQueries
+	}
+	return n + dec.n, dec.err
+}
+
+// WriteTo writes binary encoding of a Digest
+func (d *Digest) WriteTo(w io.Writer) (int64, error) {
+	enc := newEncoder(w)
+	enc.writeDigest(d)
+	return enc.n, enc.err
+}
+
+// ReadFrom decodes a Digest from reader
+func (d *Digest) ReadFrom(r io.Reader) (int64, error) {
+	dec := newDecoder(r)
+	dec.readDigest(d)
+	return dec.n, dec.err
+}
+
+// Bytes returns the binary encoding of a Digest
+func (d *Digest) Bytes() []byte {
+	res := make([]byte, len(d))
+	copy(res, d[:])
+	return res
+}
+
+// WriteTo writes binary encoding of a Proof
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	enc := newEncoder(w)
+	enc.writeElement(&proof.Point)
+	enc.writeElement(&proof.ClaimedValue)
+	enc.writeMerkleProofs(proof.Openings)
+	enc.writeProofOfProximity(&proof.ProofOfProximity)
+	return enc.n, enc.err
+}
+
+// ReadFrom decodes a Proof from reader
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	dec := newDecoder(r)
+	dec.readElement(&proof.Point)
+	dec.readElement(&proof.ClaimedValue)
+	proof.Openings = dec.readMerkleProofs()
+	dec.readProofOfProximity(&proof.ProofOfProximity)
+	return dec.n, dec.err
+}
+
+// WriteTo writes binary encoding of a BatchProofsSinglePoint
+func (proof *BatchProofsSinglePoint) WriteTo(w io.Writer) (int64, error) {
+	enc := newEncoder(w)
+	enc.writeElement(&proof.Point)
+	enc.writeElements(proof.ClaimedValues)
+	enc.writeUint64(uint64(len(proof.Openings)))
+	for i := 0; i < len(proof.Openings); i++ {
+		enc.writeMerkleProofs(proof.Openings[i])
+	}
+	enc.writeProofOfProximity(&proof.ProofOfProximity)
+	return enc.n, enc.err
+}
+
+// ReadFrom decodes a BatchProofsSinglePoint from reader
+func (proof *BatchProofsSinglePoint) ReadFrom(r io.Reader) (int64, error) {
+	dec := newDecoder(r)
+	dec.readElement(&proof.Point)
+	proof.ClaimedValues = dec.readElements()
+	proof.Openings = make([][]MerkleProof, dec.readLength())
+	for i := 0; i < len(proof.Openings); i++ {
+		proof.Openings[i] = dec.readMerkleProofs()
+	}
+	dec.readProofOfProximity(&proof.ProofOfProximity)
+	return dec.n, dec.err
+}
+
+// WriteTo writes binary encoding of a BatchProofsMultiPoints
+func (proof *BatchProofsMultiPoints) WriteTo(w io.Writer) (int64, error) {
+	enc := newEncoder(w)
+	enc.writeElements(proof.Points)
+	enc.writeElements(proof.ClaimedValues)
+	enc.writeUint64(uint64(len(proof.Openings)))
+	for i := 0; i < len(proof.Openings); i++ {
+		enc.writeMerkleProofs(proof.Openings[i])
+	}
+	enc.writeProofOfProximity(&proof.ProofOfProximity)
+	return enc.n, enc.err
+}
+
+// ReadFrom decodes a BatchProofsMultiPoints from reader
+func (proof *BatchProofsMultiPoints) ReadFrom(r io.Reader) (int64, error) {
+	dec := newDecoder(r)
+	proof.Points = dec.readElements()
+	proof.ClaimedValues = dec.readElements()
+	proof.Openings = make([][]MerkleProof, dec.readLength())
+	for i := 0; i < len(proof.Openings); i++ {
+		proof.Openings[i] = dec.readMerkleProofs()
+	}
+	dec.readProofOfProximity(&proof.ProofOfProximity)
+	return dec.n, dec.err
+}
+
+// encoder writes the binary encoding of proofs: lengths as big endian uint64,
+// field elements in regular big endian form, digests as raw bytes.
+// After an error, the following writes are no-ops.
+type encoder struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func newEncoder(w io.Writer) *encoder {
+	return &encoder{w: w}
+}
+
+func (enc *encoder) write(b []byte) {
+	if enc.err != nil {
+		return
+	}
+	var written int
+	written, enc.err = enc.w.Write(b)
+	enc.n += int64(written)
+}
+
+func (enc *encoder) writeUint64(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	enc.write(b[:])
+}
+
+func (enc *encoder) writeElement(e *fr.Element) {
+	b := e.Bytes()
+	enc.write(b[:])
+}
+
+func (enc *encoder) writeElements(e []fr.Element) {
+	enc.writeUint64(uint64(len(e)))
+	for i := 0; i < len(e); i++ {
+		enc.writeElement(&e[i])
+	}
+}
+
+func (enc *encoder) writeDigest(d *Digest) {
+	enc.write(d[:])
+}
+
+func (enc *encoder) writeDigests(d []Digest) {
+	enc.writeUint64(uint64(len(d)))
+	for i := 0; i < len(d); i++ {
+		enc.writeDigest(&d[i])
+	}
+}
+
+func (enc *encoder) writeMerkleProofs(proofs []MerkleProof) {
+	enc.writeUint64(uint64(len(proofs)))
+	for i := 0; i < len(proofs); i++ {
+		enc.writeElement(&proofs[i].Values[0])
+		enc.writeElement(&proofs[i].Values[1])
+		enc.writeDigests(proofs[i].Path)
+	}
+}
+
+func (enc *encoder) writeProofOfProximity(pp *ProofOfProximity) {
+	enc.writeDigests(pp.Roots)
+	enc.writeUint64(uint64(len(pp.Queries)))
+	for i := 0; i < len(pp.Queries); i++ {
+		enc.writeMerkleProofs(pp.Queries[i])
+	}
+	enc.writeElement(&pp.Final)
+}
+
+// decoder reads the binary encoding written by encoder.
+// After an error, the following reads return zero values.
+type decoder struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func newDecoder(r io.Reader) *decoder {
+	return &decoder{r: r}
+}
+
+func (dec *decoder) read(b []byte) {
+	if dec.err != nil {
+		return
+	}
+	var read int
+	read, dec.err = io.ReadFull(dec.r, b)
+	dec.n += int64(read)
+}
+
+func (dec *decoder) readUint64() uint64 {
+	var b [8]byte
+	dec.read(b[:])
+	if dec.err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// readLength reads a slice length, and checks it is not absurdly large
+func (dec *decoder) readLength() int {
+	l := dec.readUint64()
+	if l > maxSliceLength {
+		if dec.err == nil {
+			dec.err = errInvalidLength
+		}
+		return 0
+	}
+	return int(l)
+}
+
+func (dec *decoder) readElement(e *fr.Element) {
+	var b [fr.Bytes]byte
+	dec.read(b[:])
+	e.SetBytes(b[:])
+}
+
+func (dec *decoder) readElements() []fr.Element {
+	res := make([]fr.Element, dec.readLength())
+	for i := 0; i < len(res); i++ {
+		dec.readElement(&res[i])
+	}
+	return res
+}
+
+func (dec *decoder) readDigest(d *Digest) {
+	dec.read(d[:])
+}
+
+func (dec *decoder) readDigests() []Digest {
+	res := make([]Digest, dec.readLength())
+	for i := 0; i < len(res); i++ {
+		dec.readDigest(&res[i])
+	}
+	return res
+}
+
+func (dec *decoder) readMerkleProofs() []MerkleProof {
+	res := make([]MerkleProof, dec.readLength())
+	for i := 0; i < len(res); i++ {
+		dec.readElement(&res[i].Values[0])
+		dec.readElement(&res[i].Values[1])
+		res[i].Path = dec.readDigests()
+	}
+	return res
+}
+
+func (dec *decoder) readProofOfProximity(pp *ProofOfProximity) {
+	pp.Roots = dec.readDigests()
+	pp.Queries = make([][]MerkleProof, dec.readLength())
+	for i := 0; i < len(pp.Queries); i++ {
+		pp.Queries[i] = dec.readMerkleProofs()
+	}
+	dec.readElement(&pp.Final)
+}
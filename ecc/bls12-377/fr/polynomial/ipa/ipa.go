@@ -0,0 +1,813 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+	"strconv"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12377_pol "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidNbDigests              = errors.New("number of digests is not the same as the number of polynomials")
+	ErrInvalidNbPoints               = errors.New("number of points is not the same as the number of polynomials")
+	ErrInvalidPolynomialSize         = errors.New("invalid polynomial size (larger than SRS or == 0)")
+	ErrInvalidSRSSize                = errors.New("the size of the SRS must be at least 2")
+	ErrInvalidType                   = errors.New("the arguments do not have the types expected by the IPA scheme")
+	ErrVerifyOpeningProof            = errors.New("can't verify opening proof")
+	ErrVerifyBatchOpeningSinglePoint = errors.New("can't verify batch opening proof at single point")
+	ErrVerifyBatchOpeningMultiPoints = errors.New("can't verify batch opening proof at multiple points")
+)
+
+// dstGenerators domain separation tag used to derive the generators of the SRS
+var dstGenerators = []byte("GNARK_CRYPTO_IPA_BLS12377_G1_SVDW")
+
+// Digest commitment of a polynomial.
+type Digest bls12377.G1Affine
+
+// Scheme stores IPA data
+type Scheme struct {
+	// SRS stores the public generators
+	SRS SRS
+}
+
+// SRS stores the generators of the scheme. They are derived from hash to curve,
+// so that nobody knows a discrete log relation between them: no trusted setup is needed.
+type SRS struct {
+	G []bls12377.G1Affine // generators used to commit to the coefficients, len(G) is a power of 2
+	U bls12377.G1Affine   // generator used to commit to the inner product
+}
+
+// Proof IPA proof for opening at a single point.
+//
+// With n the size of the SRS, the proof contains log(n) pairs (L, R) of cross terms,
+// one per folding round of the inner product argument, and the final folded coefficient.
+type Proof struct {
+
+	// Point at which the polynomial is evaluated
+	Point fr.Element
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+
+	// L, R cross terms of the folding rounds
+	L, R []bls12377.G1Affine
+
+	// A folded coefficient of the polynomial
+	A fr.Element
+}
+
+// BatchProofsSinglePoint opening proof for many polynomials at the same point.
+//
+// The polynomials are folded with powers of a challenge gamma, and the folded
+// polynomial is opened using the inner product argument.
+type BatchProofsSinglePoint struct {
+
+	// Point at which the polynomials are evaluated
+	Point fr.Element
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+
+	// L, R cross terms of the folding rounds
+	L, R []bls12377.G1Affine
+
+	// A folded coefficient of Sum_i gamma**i*f_i
+	A fr.Element
+}
+
+// BatchProofsMultiPoints opening proof for many polynomials, each one
+// at its own point.
+//
+// As for KZG, the claims are reduced to a single opening following
+// https://eprint.iacr.org/2020/081.pdf (SHPLONK): with gamma and z two
+// challenges, and (f_i, z_i, y_i) the opened polynomials, points and values,
+// W is the commitment to h(X) = Sum_i gamma**i*(f_i(X) - y_i)/(X - z_i), and
+// L(X) = Sum_i gamma**i/(z - z_i)*(f_i(X) - y_i) - h(X) is opened at z, where it vanishes.
+type BatchProofsMultiPoints struct {
+
+	// Points at which the polynomials are evaluated
+	Points []fr.Element
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+
+	// W commitment to Sum_i gamma**i*(f_i(X) - y_i)/(X - z_i)
+	W bls12377.G1Affine
+
+	// L, R cross terms of the folding rounds of the opening of L(X) at z
+	L, R []bls12377.G1Affine
+
+	// A folded coefficient of L(X)
+	A fr.Element
+}
+
+// NewSRS returns a new SRS with at least size generators (the size is rounded up to
+// the next power of 2). The generators are derived using HashToCurveG1Svdw.
+func NewSRS(size uint64) (*SRS, error) {
+	if size < 2 {
+		return nil, ErrInvalidSRSSize
+	}
+	n := uint64(1) << bits.Len64(size-1)
+
+	var srs SRS
+	srs.G = make([]bls12377.G1Affine, n)
+
+	var chErr = make(chan error, 1)
+	parallel.Execute(int(n), func(start, end int) {
+		var msg [9]byte
+		msg[0] = 'G'
+		for i := start; i < end; i++ {
+			binary.BigEndian.PutUint64(msg[1:], uint64(i))
+			g, err := bls12377.HashToCurveG1Svdw(msg[:], dstGenerators)
+			if err != nil {
+				select {
+				case chErr <- err:
+				default:
+				}
+				return
+			}
+			srs.G[i] = g
+		}
+	})
+	select {
+	case err := <-chErr:
+		return nil, err
+	default:
+	}
+
+	var err error
+	srs.U, err = bls12377.HashToCurveG1Svdw([]byte{'U'}, dstGenerators)
+	if err != nil {
+		return nil, err
+	}
+
+	return &srs, nil
+}
+
+// NewScheme returns a new IPA scheme, with a SRS of at least the given size.
+func NewScheme(size uint64) (*Scheme, error) {
+	srs, err := NewSRS(size)
+	if err != nil {
+		return nil, err
+	}
+	return &Scheme{SRS: *srs}, nil
+}
+
+// Commit commits to p using a multi exponentiation with the SRS.
+func (s *Scheme) Commit(p bls12377_pol.Polynomial) (Digest, error) {
+
+	if len(p) == 0 || len(p) > len(s.SRS.G) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	var res bls12377.G1Affine
+	res.MultiExp(s.SRS.G[:len(p)], toRegular(p))
+
+	return Digest(res), nil
+}
+
+// Open computes an opening proof of p at point, digest being the commitment to p.
+func (s *Scheme) Open(point *fr.Element, digest *Digest, p bls12377_pol.Polynomial) (Proof, error) {
+
+	if len(p) == 0 || len(p) > len(s.SRS.G) {
+		return Proof{}, ErrInvalidPolynomialSize
+	}
+
+	res := Proof{
+		Point:        *point,
+		ClaimedValue: p.Evaluate(*point),
+	}
+
+	// the challenges are bound to the digest
+	fs := s.newTranscript()
+	if err := fs.Bind("w", digest.Bytes()); err != nil {
+		return Proof{}, err
+	}
+
+	var err error
+	res.L, res.R, res.A, err = s.prove(&fs, p, &res.Point, &res.ClaimedValue)
+	if err != nil {
+		return Proof{}, err
+	}
+
+	return res, nil
+}
+
+// Verify verifies an IPA opening proof at a single point.
+func (s *Scheme) Verify(digest *Digest, proof *Proof) error {
+
+	fs := s.newTranscript()
+	if err := fs.Bind("w", digest.Bytes()); err != nil {
+		return err
+	}
+
+	digests := []bls12377.G1Affine{
+		bls12377.G1Affine(*digest),
+	}
+	var one fr.Element
+	one.SetOne()
+	return s.check(&fs, digests, []fr.Element{one},
+		&proof.Point, &proof.ClaimedValue, proof.L, proof.R, &proof.A,
+		ErrVerifyOpeningProof)
+}
+
+// BatchOpenSinglePoint creates a batch opening proof at point of a list of polynomials.
+// The digests of the polynomials are bound to the Fiat Shamir challenges.
+func (s *Scheme) BatchOpenSinglePoint(point *fr.Element, digests []Digest, polynomials []bls12377_pol.Polynomial) (BatchProofsSinglePoint, error) {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(polynomials) {
+		return BatchProofsSinglePoint{}, ErrInvalidNbDigests
+	}
+
+	// compute the purported values
+	res := BatchProofsSinglePoint{Point: *point}
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	largestPoly := -1
+	for i := 0; i < len(polynomials); i++ {
+		if len(polynomials[i]) == 0 || len(polynomials[i]) > len(s.SRS.G) {
+			return BatchProofsSinglePoint{}, ErrInvalidPolynomialSize
+		}
+		res.ClaimedValues[i] = polynomials[i].Evaluate(*point)
+		if len(polynomials[i]) > largestPoly {
+			largestPoly = len(polynomials[i])
+		}
+	}
+
+	// derive the challenge gamma, binded to the point, the commitments and the values
+	fs := s.newTranscript("gamma")
+	gamma, err := deriveGamma(&fs, []fr.Element{*point}, digests, res.ClaimedValues)
+	if err != nil {
+		return BatchProofsSinglePoint{}, err
+	}
+
+	// fold the claimed values and the polynomials
+	var foldedEvaluations fr.Element
+	foldedPolynomials := make(bls12377_pol.Polynomial, largestPoly)
+	var gammaI fr.Element
+	gammaI.SetOne()
+	for i := 0; i < len(polynomials); i++ {
+		var t fr.Element
+		for j := 0; j < len(polynomials[i]); j++ {
+			t.Mul(&polynomials[i][j], &gammaI)
+			foldedPolynomials[j].Add(&foldedPolynomials[j], &t)
+		}
+		t.Mul(&res.ClaimedValues[i], &gammaI)
+		foldedEvaluations.Add(&foldedEvaluations, &t)
+		gammaI.Mul(&gammaI, &gamma)
+	}
+
+	// open the folded polynomial
+	res.L, res.R, res.A, err = s.prove(&fs, foldedPolynomials, &res.Point, &foldedEvaluations)
+	if err != nil {
+		return BatchProofsSinglePoint{}, err
+	}
+
+	return res, nil
+}
+
+// BatchVerifySinglePoint verifies a batched opening proof at a single point of a list of polynomials.
+//
+// The folded digest Sum_i gamma**i*[f_i] is not computed, it is part of the
+// multi exponentiation of the verification of the inner product argument.
+func (s *Scheme) BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchProofsSinglePoint) error {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+
+	// derive the challenge gamma, binded to the point, the commitments and the values
+	fs := s.newTranscript("gamma")
+	gamma, err := deriveGamma(&fs, []fr.Element{batchOpeningProof.Point}, digests, batchOpeningProof.ClaimedValues)
+	if err != nil {
+		return err
+	}
+
+	// fold the claimed values
+	gammai := make([]fr.Element, nbDigests)
+	gammai[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+	var foldedEvaluations, t fr.Element
+	for i := 0; i < nbDigests; i++ {
+		t.Mul(&batchOpeningProof.ClaimedValues[i], &gammai[i])
+		foldedEvaluations.Add(&foldedEvaluations, &t)
+	}
+
+	points := make([]bls12377.G1Affine, nbDigests)
+	for i := 0; i < nbDigests; i++ {
+		points[i] = bls12377.G1Affine(digests[i])
+	}
+
+	return s.check(&fs, points, gammai,
+		&batchOpeningProof.Point, &foldedEvaluations,
+		batchOpeningProof.L, batchOpeningProof.R, &batchOpeningProof.A,
+		ErrVerifyBatchOpeningSinglePoint)
+}
+
+// BatchOpenMultiPoints creates a batch opening proof of a list of polynomials, the i-th polynomial
+// being opened at the i-th point.
+// The digests of the polynomials are bound to the Fiat Shamir challenges.
+func (s *Scheme) BatchOpenMultiPoints(points []fr.Element, digests []Digest, polynomials []bls12377_pol.Polynomial) (BatchProofsMultiPoints, error) {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(polynomials) {
+		return BatchProofsMultiPoints{}, ErrInvalidNbDigests
+	}
+	if len(points) != len(polynomials) {
+		return BatchProofsMultiPoints{}, ErrInvalidNbPoints
+	}
+
+	// compute the purported values
+	var res BatchProofsMultiPoints
+	res.Points = make([]fr.Element, len(points))
+	copy(res.Points, points)
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	largestPoly := -1
+	for i := 0; i < len(polynomials); i++ {
+		if len(polynomials[i]) == 0 || len(polynomials[i]) > len(s.SRS.G) {
+			return BatchProofsMultiPoints{}, ErrInvalidPolynomialSize
+		}
+		res.ClaimedValues[i] = polynomials[i].Evaluate(points[i])
+		if len(polynomials[i]) > largestPoly {
+			largestPoly = len(polynomials[i])
+		}
+	}
+
+	// derive the challenge gamma, binded to the points, the commitments and the values
+	fs := s.newTranscript("gamma", "z")
+	gamma, err := deriveGamma(&fs, points, digests, res.ClaimedValues)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	// h = Sum_i gamma**i*(f_i - y_i)/(X - z_i)
+	h := make(bls12377_pol.Polynomial, largestPoly-1)
+	var gammaI, t fr.Element
+	gammaI.SetOne()
+	for i := 0; i < len(polynomials); i++ {
+		q, _ := polynomials[i].DivideByXMinusA(points[i])
+		for j := 0; j < len(q); j++ {
+			t.Mul(&q[j], &gammaI)
+			h[j].Add(&h[j], &t)
+		}
+		gammaI.Mul(&gammaI, &gamma)
+	}
+	if len(h) > 0 {
+		w, err := s.Commit(h)
+		if err != nil {
+			return BatchProofsMultiPoints{}, err
+		}
+		res.W = bls12377.G1Affine(w)
+	}
+
+	// derive the challenge z, binded to W
+	z, err := deriveZ(&fs, &res.W)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	// c_i = gamma**i / (z - z_i)
+	c, err := computeMultiPointsCoefficients(gamma, z, points)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	// L = Sum_i c_i*(f_i - y_i) - h, which vanishes at z
+	l := make(bls12377_pol.Polynomial, largestPoly)
+	for i := 0; i < len(polynomials); i++ {
+		for j := 0; j < len(polynomials[i]); j++ {
+			t.Mul(&polynomials[i][j], &c[i])
+			l[j].Add(&l[j], &t)
+		}
+		t.Mul(&res.ClaimedValues[i], &c[i])
+		l[0].Sub(&l[0], &t)
+	}
+	for j := 0; j < len(h); j++ {
+		l[j].Sub(&l[j], &h[j])
+	}
+
+	// open L at z
+	var zero fr.Element
+	res.L, res.R, res.A, err = s.prove(&fs, l, &z, &zero)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	return res, nil
+}
+
+// BatchVerifyMultiPoints verifies a batched opening proof of a list of polynomials at multiple points.
+//
+// With c_i = gamma**i / (z - z_i), the commitment to L(X) is
+// Sum_i c_i*[f_i] - (Sum_i c_i*y_i)*G_0 - W, which is opened at z for the value 0.
+// As for the single point case, this commitment is part of the multi exponentiation
+// of the verification of the inner product argument.
+func (s *Scheme) BatchVerifyMultiPoints(digests []Digest, batchOpeningProof *BatchProofsMultiPoints) error {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	if len(batchOpeningProof.Points) != nbDigests {
+		return ErrInvalidNbPoints
+	}
+
+	// derive the challenges gamma and z
+	fs := s.newTranscript("gamma", "z")
+	gamma, err := deriveGamma(&fs, batchOpeningProof.Points, digests, batchOpeningProof.ClaimedValues)
+	if err != nil {
+		return err
+	}
+	z, err := deriveZ(&fs, &batchOpeningProof.W)
+	if err != nil {
+		return err
+	}
+
+	// c_i = gamma**i / (z - z_i)
+	c, err := computeMultiPointsCoefficients(gamma, z, batchOpeningProof.Points)
+	if err != nil {
+		return ErrVerifyBatchOpeningMultiPoints
+	}
+
+	// [L] = Sum_i c_i*[f_i] - (Sum_i c_i*y_i)*G_0 - W
+	points := make([]bls12377.G1Affine, nbDigests+2)
+	scalars := make([]fr.Element, nbDigests+2)
+	var foldedEvaluations, t fr.Element
+	for i := 0; i < nbDigests; i++ {
+		points[i] = bls12377.G1Affine(digests[i])
+		scalars[i] = c[i]
+		t.Mul(&batchOpeningProof.ClaimedValues[i], &c[i])
+		foldedEvaluations.Add(&foldedEvaluations, &t)
+	}
+	points[nbDigests] = s.SRS.G[0]
+	scalars[nbDigests].Neg(&foldedEvaluations)
+	points[nbDigests+1] = batchOpeningProof.W
+	scalars[nbDigests+1].SetOne().Neg(&scalars[nbDigests+1])
+
+	var zero fr.Element
+	return s.check(&fs, points, scalars,
+		&z, &zero,
+		batchOpeningProof.L, batchOpeningProof.R, &batchOpeningProof.A,
+		ErrVerifyBatchOpeningMultiPoints)
+}
+
+// prove runs the inner product argument, proving that <p, (1, point, point**2, ...)> = value,
+// p being committed to with the generators G of the SRS.
+//
+// The challenges w, u_0, u_1, ... are derived from fs, in which the commitment to p must
+// have been bound, directly or through a previous challenge.
+//
+// With U' = [w]U, at each round the vectors a (initially the coefficients of p),
+// b (initially the powers of point) and the generators G are split in halves, and
+// L = <a_lo, G_hi> + <a_lo, b_hi>U', R = <a_hi, G_lo> + <a_hi, b_lo>U',
+// a' = u*a_lo + u**-1*a_hi, b' = u**-1*b_lo + u*b_hi, G' = u**-1*G_lo + u*G_hi.
+// After log(n) rounds, a is a single scalar A.
+func (s *Scheme) prove(fs *fiatshamir.Transcript, p bls12377_pol.Polynomial, point, value *fr.Element) ([]bls12377.G1Affine, []bls12377.G1Affine, fr.Element, error) {
+
+	n := len(s.SRS.G)
+	nbRounds := bits.TrailingZeros(uint(n))
+
+	// derive w, U' = [w]U
+	w, err := deriveW(fs, point, value)
+	if err != nil {
+		return nil, nil, fr.Element{}, err
+	}
+	var bW big.Int
+	w.ToBigIntRegular(&bW)
+	var uPrime bls12377.G1Affine
+	uPrime.ScalarMultiplication(&s.SRS.U, &bW)
+
+	// a = p, padded with zeroes, b = powers of point
+	a := make([]fr.Element, n)
+	copy(a, p)
+	b := make([]fr.Element, n)
+	b[0].SetOne()
+	for i := 1; i < n; i++ {
+		b[i].Mul(&b[i-1], point)
+	}
+	g := s.SRS.G
+
+	L := make([]bls12377.G1Affine, nbRounds)
+	R := make([]bls12377.G1Affine, nbRounds)
+	for j := 0; j < nbRounds; j++ {
+		m := len(a) / 2
+
+		// cross terms
+		cL := innerProduct(a[:m], b[m:])
+		cR := innerProduct(a[m:], b[:m])
+		L[j] = commitWithInnerProduct(g[m:], a[:m], &uPrime, &cL)
+		R[j] = commitWithInnerProduct(g[:m], a[m:], &uPrime, &cR)
+
+		// challenge u_j, binded to L and R
+		u, err := deriveU(fs, j, &L[j], &R[j])
+		if err != nil {
+			return nil, nil, fr.Element{}, err
+		}
+		var uInv fr.Element
+		uInv.Inverse(&u)
+
+		// fold a, b and g
+		var t fr.Element
+		for i := 0; i < m; i++ {
+			a[i].Mul(&a[i], &u)
+			t.Mul(&a[m+i], &uInv)
+			a[i].Add(&a[i], &t)
+
+			b[i].Mul(&b[i], &uInv)
+			t.Mul(&b[m+i], &u)
+			b[i].Add(&b[i], &t)
+		}
+		a = a[:m]
+		b = b[:m]
+		g = foldGenerators(g, &uInv, &u)
+	}
+
+	return L, R, a[0], nil
+}
+
+// check verifies an inner product argument, for the polynomial committed to with
+// Sum_i digestScalars[i]*digests[i], at point, for the given value.
+//
+// With s_i the coefficients such that G_final = Sum_i s_i*G_i (s_i is the product of
+// the u_j or their inverses, depending on the bits of i), and
+// b_final = Prod_j (u_j**-1 + u_j*point**(2**(log(n)-1-j))),
+// the verifier checks with a single multi exponentiation that
+// Sum_i A*s_i*G_i + w*(A*b_final - value)*U - C - Sum_j (u_j**2*L_j + u_j**-2*R_j) == 0,
+// C being the commitment to the polynomial.
+func (s *Scheme) check(fs *fiatshamir.Transcript, digests []bls12377.G1Affine, digestScalars []fr.Element, point, value *fr.Element, L, R []bls12377.G1Affine, A *fr.Element, errVerify error) error {
+
+	n := len(s.SRS.G)
+	nbRounds := bits.TrailingZeros(uint(n))
+	if len(L) != nbRounds || len(R) != nbRounds {
+		return errVerify
+	}
+
+	// derive the challenges
+	w, err := deriveW(fs, point, value)
+	if err != nil {
+		return err
+	}
+	u := make([]fr.Element, nbRounds)
+	for j := 0; j < nbRounds; j++ {
+		if u[j], err = deriveU(fs, j, &L[j], &R[j]); err != nil {
+			return err
+		}
+		if u[j].IsZero() {
+			return errVerify
+		}
+	}
+	uInv := bls12377_pol.BatchInvert(u)
+
+	// s_i, the bit of round j being the (j+1)-th most significant bit of i
+	sCoeffs := make([]fr.Element, 1, n)
+	sCoeffs[0].SetOne()
+	for j := 0; j < nbRounds; j++ {
+		m := len(sCoeffs)
+		sCoeffs = sCoeffs[:2*m]
+		for i := m - 1; i >= 0; i-- {
+			sCoeffs[2*i+1].Mul(&sCoeffs[i], &u[j])
+			sCoeffs[2*i].Mul(&sCoeffs[i], &uInv[j])
+		}
+	}
+
+	// b_final
+	var bFinal, t fr.Element
+	bFinal.SetOne()
+	pointPow := *point // point**(2**(nbRounds-1-j)), from the last round
+	for j := nbRounds - 1; j >= 0; j-- {
+		t.Mul(&pointPow, &u[j]).Add(&t, &uInv[j])
+		bFinal.Mul(&bFinal, &t)
+		pointPow.Square(&pointPow)
+	}
+
+	// points and scalars of the multi exponentiation
+	nbPoints := n + 1 + len(digests) + 2*nbRounds
+	points := make([]bls12377.G1Affine, 0, nbPoints)
+	scalars := make([]fr.Element, 0, nbPoints)
+
+	points = append(points, s.SRS.G...)
+	for i := 0; i < n; i++ {
+		sCoeffs[i].Mul(&sCoeffs[i], A)
+	}
+	scalars = append(scalars, sCoeffs...)
+
+	points = append(points, s.SRS.U)
+	t.Mul(A, &bFinal).Sub(&t, value).Mul(&t, &w)
+	scalars = append(scalars, t)
+
+	points = append(points, digests...)
+	for i := 0; i < len(digestScalars); i++ {
+		t.Neg(&digestScalars[i])
+		scalars = append(scalars, t)
+	}
+
+	points = append(points, L...)
+	points = append(points, R...)
+	for j := 0; j < nbRounds; j++ {
+		t.Square(&u[j]).Neg(&t)
+		scalars = append(scalars, t)
+	}
+	for j := 0; j < nbRounds; j++ {
+		t.Square(&uInv[j]).Neg(&t)
+		scalars = append(scalars, t)
+	}
+
+	var res bls12377.G1Jac
+	res.MultiExp(points, toRegular(scalars))
+	if !res.Z.IsZero() {
+		return errVerify
+	}
+
+	return nil
+}
+
+// newTranscript returns a transcript with the given challenges, followed by the
+// challenges of the inner product argument
+func (s *Scheme) newTranscript(challenges ...string) fiatshamir.Transcript {
+	nbRounds := bits.TrailingZeros(uint(len(s.SRS.G)))
+	challenges = append(challenges, "w")
+	for j := 0; j < nbRounds; j++ {
+		challenges = append(challenges, challengeU(j))
+	}
+	return fiatshamir.NewTranscript(fiatshamir.SHA256, challenges...)
+}
+
+// challengeU returns the name of the challenge of the j-th round
+func challengeU(j int) string {
+	return "u" + strconv.Itoa(j)
+}
+
+// deriveW derives the challenge w, binded to the point and the value
+func deriveW(fs *fiatshamir.Transcript, point, value *fr.Element) (fr.Element, error) {
+	b := point.Bytes()
+	if err := fs.Bind("w", b[:]); err != nil {
+		return fr.Element{}, err
+	}
+	b = value.Bytes()
+	if err := fs.Bind("w", b[:]); err != nil {
+		return fr.Element{}, err
+	}
+	return computeChallenge(fs, "w")
+}
+
+// deriveU derives the challenge of the j-th round, binded to L and R
+func deriveU(fs *fiatshamir.Transcript, j int, L, R *bls12377.G1Affine) (fr.Element, error) {
+	name := challengeU(j)
+	b := L.Bytes()
+	if err := fs.Bind(name, b[:]); err != nil {
+		return fr.Element{}, err
+	}
+	b = R.Bytes()
+	if err := fs.Bind(name, b[:]); err != nil {
+		return fr.Element{}, err
+	}
+	return computeChallenge(fs, name)
+}
+
+// deriveGamma derives the challenge gamma used to fold the polynomials,
+// binded to the points, the digests and the claimed values.
+func deriveGamma(fs *fiatshamir.Transcript, points []fr.Element, digests []Digest, claimedValues []fr.Element) (fr.Element, error) {
+	for i := 0; i < len(points); i++ {
+		b := points[i].Bytes()
+		if err := fs.Bind("gamma", b[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := 0; i < len(digests); i++ {
+		if err := fs.Bind("gamma", digests[i].Bytes()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := 0; i < len(claimedValues); i++ {
+		b := claimedValues[i].Bytes()
+		if err := fs.Bind("gamma", b[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	return computeChallenge(fs, "gamma")
+}
+
+// deriveZ derives the challenge z of the multi points batch opening, binded to W.
+func deriveZ(fs *fiatshamir.Transcript, w *bls12377.G1Affine) (fr.Element, error) {
+	b := w.Bytes()
+	if err := fs.Bind("z", b[:]); err != nil {
+		return fr.Element{}, err
+	}
+	return computeChallenge(fs, "z")
+}
+
+// computeChallenge computes the challenge name and converts it to a fr.Element
+func computeChallenge(fs *fiatshamir.Transcript, name string) (fr.Element, error) {
+	b, err := fs.ComputeChallenge(name)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(b)
+	return res, nil
+}
+
+// computeMultiPointsCoefficients returns gamma**i / (z - points[i])
+func computeMultiPointsCoefficients(gamma, z fr.Element, points []fr.Element) ([]fr.Element, error) {
+	res := make([]fr.Element, len(points))
+	for i := 0; i < len(points); i++ {
+		res[i].Sub(&z, &points[i])
+		if res[i].IsZero() {
+			return nil, ErrVerifyBatchOpeningMultiPoints
+		}
+	}
+	res = bls12377_pol.BatchInvert(res)
+	var gammaI fr.Element
+	gammaI.SetOne()
+	for i := 0; i < len(res); i++ {
+		res[i].Mul(&res[i], &gammaI)
+		gammaI.Mul(&gammaI, &gamma)
+	}
+	return res, nil
+}
+
+// innerProduct returns <a, b>
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, t fr.Element
+	for i := 0; i < len(a); i++ {
+		t.Mul(&a[i], &b[i])
+		res.Add(&res, &t)
+	}
+	return res
+}
+
+// commitWithInnerProduct returns <a, g> + c*uPrime
+func commitWithInnerProduct(g []bls12377.G1Affine, a []fr.Element, uPrime *bls12377.G1Affine, c *fr.Element) bls12377.G1Affine {
+	points := make([]bls12377.G1Affine, len(g)+1)
+	copy(points, g)
+	points[len(g)] = *uPrime
+	scalars := make([]fr.Element, len(a)+1)
+	copy(scalars, a)
+	scalars[len(a)] = *c
+
+	var res bls12377.G1Affine
+	res.MultiExp(points, toRegular(scalars))
+	return res
+}
+
+// foldGenerators returns uInv*g_lo + u*g_hi
+func foldGenerators(g []bls12377.G1Affine, uInv, u *fr.Element) []bls12377.G1Affine {
+	m := len(g) / 2
+	var bUInv, bU big.Int
+	uInv.ToBigIntRegular(&bUInv)
+	u.ToBigIntRegular(&bU)
+
+	res := make([]bls12377.G1Jac, m)
+	parallel.Execute(m, func(start, end int) {
+		var hi bls12377.G1Jac
+		for i := start; i < end; i++ {
+			res[i].FromAffine(&g[i])
+			res[i].ScalarMultiplication(&res[i], &bUInv)
+			hi.FromAffine(&g[m+i])
+			hi.ScalarMultiplication(&hi, &bU)
+			res[i].AddAssign(&hi)
+		}
+	})
+
+	resAff := make([]bls12377.G1Affine, m)
+	bls12377.BatchJacobianToAffineG1(res, resAff)
+	return resAff
+}
+
+// toRegular returns a copy of the scalars in regular form, as expected by the multi exponentiation
+func toRegular(scalars []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(scalars))
+	parallel.Execute(len(scalars), func(start, end int) {
+		for i := start; i < end; i++ {
+			res[i] = scalars[i]
+			res[i].FromMont()
+		}
+	})
+	return res
+}
@@ -0,0 +1,686 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12377_pol "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	"github.com/consensys/gnark-crypto/polynomial"
+)
+
+// testScheme IPA scheme with a SRS of size 64
+var testScheme *Scheme
+
+func init() {
+	const srsSize = 64
+	var err error
+	testScheme, err = NewScheme(srsSize)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func randomPolynomial(size int) bls12377_pol.Polynomial {
+	f := make(bls12377_pol.Polynomial, size)
+	for i := 0; i < size; i++ {
+		f[i].SetRandom()
+	}
+	return f
+}
+
+// commitAll commits to each polynomial of f
+func commitAll(s *Scheme, f []bls12377_pol.Polynomial) ([]Digest, error) {
+	digests := make([]Digest, len(f))
+	for i := 0; i < len(f); i++ {
+		var err error
+		digests[i], err = s.Commit(f[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return digests, nil
+}
+
+func TestSerializationSRS(t *testing.T) {
+
+	// create a SRS
+	srs, err := NewSRS(64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// serialize it...
+	var buf bytes.Buffer
+	_, err = srs.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reconstruct the SRS
+	var _srs SRS
+	_, err = _srs.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compare
+	if !reflect.DeepEqual(srs, &_srs) {
+		t.Fatal("scheme serialization failed")
+	}
+
+}
+
+func TestCommit(t *testing.T) {
+
+	// create a polynomial
+	f := randomPolynomial(60)
+
+	// commit using the method from IPA
+	ipaCommit, err := testScheme.Commit(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// check commitment using a manual sum
+	var manualCommit, tmp bls12377.G1Jac
+	var bi big.Int
+	for i := 0; i < len(f); i++ {
+		f[i].ToBigIntRegular(&bi)
+		tmp.FromAffine(&testScheme.SRS.G[i])
+		tmp.ScalarMultiplication(&tmp, &bi)
+		manualCommit.AddAssign(&tmp)
+	}
+	var manualCommitAff bls12377.G1Affine
+	manualCommitAff.FromJacobian(&manualCommit)
+
+	// compare both results
+	if !manualCommitAff.Equal((*bls12377.G1Affine)(&ipaCommit)) {
+		t.Fatal("error IPA commitment")
+	}
+
+}
+
+func TestNewSRS(t *testing.T) {
+
+	// the size is rounded up to the next power of 2
+	srs, err := NewSRS(33)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srs.G) != 64 {
+		t.Fatal("the size of the SRS should be a power of 2")
+	}
+
+	// the generators are deterministic and distinct
+	for i := 0; i < len(srs.G); i++ {
+		if !srs.G[i].Equal(&testScheme.SRS.G[i]) {
+			t.Fatal("the generators should be deterministic")
+		}
+		if !srs.G[i].IsInSubGroup() || srs.G[i].IsInfinity() || srs.G[i].Equal(&srs.U) {
+			t.Fatal("invalid generator")
+		}
+		if i > 0 && srs.G[i].Equal(&srs.G[i-1]) {
+			t.Fatal("the generators should be distinct")
+		}
+	}
+
+	if _, err := NewSRS(1); err != ErrInvalidSRSSize {
+		t.Fatal("a SRS of size 1 should be rejected")
+	}
+}
+
+func TestCommitInvalidSize(t *testing.T) {
+
+	// a polynomial larger than the SRS cannot be committed to
+	f := randomPolynomial(len(testScheme.SRS.G) + 1)
+	if _, err := testScheme.Commit(f); err != ErrInvalidPolynomialSize {
+		t.Fatal("commitment to a polynomial larger than the SRS should fail")
+	}
+	if _, err := testScheme.Commit(nil); err != ErrInvalidPolynomialSize {
+		t.Fatal("commitment to an empty polynomial should fail")
+	}
+
+}
+
+func TestVerifySinglePoint(t *testing.T) {
+
+	// create a polynomial
+	f := randomPolynomial(60)
+
+	// commit the polynomial
+	digest, err := testScheme.Commit(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compute opening proof at a random point
+	var point fr.Element
+	point.SetString("4321")
+	proof, err := testScheme.Open(&point, &digest, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed valued
+	expected := f.Evaluate(point)
+	if !proof.ClaimedValue.Equal(&expected) {
+		t.Fatal("inconsistant claimed value")
+	}
+
+	// the proof is logarithmic in the size of the SRS
+	if len(proof.L) != 6 || len(proof.R) != 6 {
+		t.Fatal("the proof should contain log(n) cross terms")
+	}
+
+	// verify correct proof
+	err = testScheme.Verify(&digest, &proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify wrong proof
+	proof.ClaimedValue.Double(&proof.ClaimedValue)
+	err = testScheme.Verify(&digest, &proof)
+	if err == nil {
+		t.Fatal("verifying wrong proof should have failed")
+	}
+
+	// verify proof with a tampered cross term
+	proof.ClaimedValue.Set(&expected)
+	proof.L[2], proof.R[2] = proof.R[2], proof.L[2]
+	err = testScheme.Verify(&digest, &proof)
+	if err == nil {
+		t.Fatal("verifying proof with tampered cross terms should have failed")
+	}
+	proof.L[2], proof.R[2] = proof.R[2], proof.L[2]
+
+	// verify proof at another point
+	proof.Point.SetString("1234")
+	err = testScheme.Verify(&digest, &proof)
+	if err == nil {
+		t.Fatal("verifying proof at another point should have failed")
+	}
+}
+
+func TestVerifySinglePointConstant(t *testing.T) {
+
+	f := randomPolynomial(1)
+	digest, err := testScheme.Commit(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var point fr.Element
+	point.SetRandom()
+	proof, err := testScheme.Open(&point, &digest, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := testScheme.Verify(&digest, &proof); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBatchVerifySinglePoint(t *testing.T) {
+
+	// create polynomials
+	f := make([]bls12377_pol.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		f[i] = randomPolynomial(60 - i)
+	}
+
+	// commit the polynomials
+	digests, err := commitAll(testScheme, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compute opening proof at a random point
+	var point fr.Element
+	point.SetString("4321")
+	proof, err := testScheme.BatchOpenSinglePoint(&point, digests, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed values
+	for i := 0; i < 10; i++ {
+		expectedClaim := f[i].Evaluate(point)
+		if !expectedClaim.Equal(&proof.ClaimedValues[i]) {
+			t.Fatal("inconsistant claimed values")
+		}
+	}
+
+	// verify correct proof
+	err = testScheme.BatchVerifySinglePoint(digests, &proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify wrong proof
+	var claimedValue fr.Element
+	claimedValue.Set(&proof.ClaimedValues[0])
+	proof.ClaimedValues[0].Double(&proof.ClaimedValues[0])
+	err = testScheme.BatchVerifySinglePoint(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying wrong proof should have failed")
+	}
+	proof.ClaimedValues[0].Set(&claimedValue)
+
+	// verify the proof against another set of digests
+	digests[0], digests[1] = digests[1], digests[0]
+	err = testScheme.BatchVerifySinglePoint(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying proof with swapped digests should have failed")
+	}
+
+}
+
+func TestBatchVerifyMultiPoints(t *testing.T) {
+
+	// create polynomials
+	f := make([]bls12377_pol.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		f[i] = randomPolynomial(60 - i)
+	}
+
+	// commit the polynomials
+	digests, err := commitAll(testScheme, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// pick the points, PLONK style: the first polynomials are opened at zeta,
+	// the others at zeta*omega
+	var zeta, omega fr.Element
+	zeta.SetRandom()
+	omega.SetRandom()
+	points := make([]fr.Element, 10)
+	for i := 0; i < 10; i++ {
+		points[i].Set(&zeta)
+		if i >= 7 {
+			points[i].Mul(&points[i], &omega)
+		}
+	}
+
+	// compute the batch opening proof
+	proof, err := testScheme.BatchOpenMultiPoints(points, digests, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify the claimed values
+	for i := 0; i < 10; i++ {
+		expectedClaim := f[i].Evaluate(points[i])
+		if !expectedClaim.Equal(&proof.ClaimedValues[i]) {
+			t.Fatal("inconsistant claimed values")
+		}
+	}
+
+	// verify correct proof
+	err = testScheme.BatchVerifyMultiPoints(digests, &proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify wrong proof
+	var claimedValue fr.Element
+	claimedValue.Set(&proof.ClaimedValues[8])
+	proof.ClaimedValues[8].Double(&proof.ClaimedValues[8])
+	err = testScheme.BatchVerifyMultiPoints(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying wrong proof should have failed")
+	}
+	proof.ClaimedValues[8].Set(&claimedValue)
+
+	// verify the proof at other points
+	proof.Points[0], proof.Points[9] = proof.Points[9], proof.Points[0]
+	err = testScheme.BatchVerifyMultiPoints(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying proof at swapped points should have failed")
+	}
+	proof.Points[0], proof.Points[9] = proof.Points[9], proof.Points[0]
+
+	// verify the proof against another set of digests
+	digests[0], digests[1] = digests[1], digests[0]
+	err = testScheme.BatchVerifyMultiPoints(digests, &proof)
+	if err == nil {
+		t.Fatal("verifying proof with swapped digests should have failed")
+	}
+
+}
+
+func TestGenericScheme(t *testing.T) {
+
+	var s polynomial.CommitmentScheme = &GenericScheme{Scheme: testScheme}
+
+	// single point
+	f := randomPolynomial(60)
+	var point fr.Element
+	point.SetRandom()
+	digest := s.Commit(f)
+	proof := s.Open(&point, f)
+	if err := s.Verify(&point, digest, proof); err != nil {
+		t.Fatal(err)
+	}
+	var otherPoint fr.Element
+	otherPoint.Double(&point)
+	if err := s.Verify(&otherPoint, digest, proof); err == nil {
+		t.Fatal("verifying proof at another point should have failed")
+	}
+
+	// batch opening at a single point
+	polynomials := []polynomial.Polynomial{f, randomPolynomial(10), randomPolynomial(30)}
+	digests := make([]polynomial.Digest, len(polynomials))
+	for i := 0; i < len(polynomials); i++ {
+		digests[i] = s.Commit(polynomials[i])
+	}
+	batchProof := s.BatchOpenSinglePoint(&point, polynomials)
+	claimedValues := batchProof.(*BatchProofsSinglePoint).ClaimedValues
+	if err := s.BatchVerifySinglePoint(&point, claimedValues, digests, batchProof); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.BatchVerifySinglePoint(&otherPoint, claimedValues, digests, batchProof); err == nil {
+		t.Fatal("verifying batch proof at another point should have failed")
+	}
+
+	// batch opening at multiple points
+	points := []fr.Element{point, otherPoint, point}
+	multiPointsProof := s.BatchOpenMultiPoints(points, polynomials)
+	claimedValues = multiPointsProof.(*BatchProofsMultiPoints).ClaimedValues
+	if err := s.BatchVerifyMultiPoints(points, claimedValues, digests, multiPointsProof); err != nil {
+		t.Fatal(err)
+	}
+	points[0], points[1] = points[1], points[0]
+	if err := s.BatchVerifyMultiPoints(points, claimedValues, digests, multiPointsProof); err == nil {
+		t.Fatal("verifying batch proof at other points should have failed")
+	}
+
+	// arguments of the wrong type
+	if err := s.Verify(point, digest, proof); err != ErrInvalidType {
+		t.Fatal("a point which is not a *fr.Element should be rejected")
+	}
+}
+
+func TestSerializationProofs(t *testing.T) {
+
+	f := randomPolynomial(60)
+	var point fr.Element
+	point.SetRandom()
+
+	polynomials := []bls12377_pol.Polynomial{f, randomPolynomial(10)}
+	digests, err := commitAll(testScheme, polynomials)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// single point opening proof
+	proof, err := testScheme.Open(&point, &digests[0], f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _proof Proof
+	if _, err := _proof.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(proof, _proof) {
+		t.Fatal("opening proof serialization failed")
+	}
+
+	// batch opening proof
+	batchProof, err := testScheme.BatchOpenSinglePoint(&point, digests, polynomials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := batchProof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _batchProof BatchProofsSinglePoint
+	if _, err := _batchProof.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(batchProof, _batchProof) {
+		t.Fatal("batch opening proof serialization failed")
+	}
+
+	// multi points batch opening proof
+	points := []fr.Element{point, fr.One()}
+	multiPointsProof, err := testScheme.BatchOpenMultiPoints(points, digests, polynomials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if _, err := multiPointsProof.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _multiPointsProof BatchProofsMultiPoints
+	if _, err := _multiPointsProof.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(multiPointsProof, _multiPointsProof) {
+		t.Fatal("multi points batch opening proof serialization failed")
+	}
+
+	// digest
+	buf.Reset()
+	if _, err := digests[0].WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var _digest Digest
+	if _, err := _digest.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(digests[0], _digest) {
+		t.Fatal("digest serialization failed")
+	}
+}
+
+const benchSize = 1 << 12
+
+func BenchmarkIPACommit(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// random polynomial
+	p := randomPolynomial(benchSize / 2)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchScheme.Commit(p)
+	}
+}
+
+func BenchmarkIPAOpen(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// random polynomial
+	p := randomPolynomial(benchSize / 2)
+	var r fr.Element
+	r.SetRandom()
+
+	// commit
+	comm, err := benchScheme.Commit(p)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = benchScheme.Open(&r, &comm, p)
+	}
+}
+
+func BenchmarkIPAVerify(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// random polynomial
+	p := randomPolynomial(benchSize / 2)
+	var r fr.Element
+	r.SetRandom()
+
+	// commit
+	comm, err := benchScheme.Commit(p)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// open
+	openingProof, err := benchScheme.Open(&r, &comm, p)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchScheme.Verify(&comm, &openingProof)
+	}
+}
+
+func BenchmarkIPABatchOpen10(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// 10 random polynomials
+	ps := make([]bls12377_pol.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		ps[i] = randomPolynomial(benchSize / 2)
+	}
+
+	// commitments
+	commitments, err := commitAll(benchScheme, ps)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var r fr.Element
+	r.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchScheme.BatchOpenSinglePoint(&r, commitments, ps)
+	}
+}
+
+func BenchmarkIPABatchVerify10(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// 10 random polynomials
+	ps := make([]bls12377_pol.Polynomial, 10)
+	for i := 0; i < 10; i++ {
+		ps[i] = randomPolynomial(benchSize / 2)
+	}
+
+	// commitments
+	commitments, err := commitAll(benchScheme, ps)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var r fr.Element
+	r.SetRandom()
+	proof, err := benchScheme.BatchOpenSinglePoint(&r, commitments, ps)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchScheme.BatchVerifySinglePoint(commitments, &proof)
+	}
+}
+
+func BenchmarkIPABatchOpenMultiPoints10(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// 10 random polynomials and points
+	ps := make([]bls12377_pol.Polynomial, 10)
+	points := make([]fr.Element, 10)
+	for i := 0; i < 10; i++ {
+		ps[i] = randomPolynomial(benchSize / 2)
+		points[i].SetRandom()
+	}
+
+	// commitments
+	commitments, err := commitAll(benchScheme, ps)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchScheme.BatchOpenMultiPoints(points, commitments, ps)
+	}
+}
+
+func BenchmarkIPABatchVerifyMultiPoints10(b *testing.B) {
+	benchScheme, err := NewScheme(benchSize)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	// 10 random polynomials and points
+	ps := make([]bls12377_pol.Polynomial, 10)
+	points := make([]fr.Element, 10)
+	for i := 0; i < 10; i++ {
+		ps[i] = randomPolynomial(benchSize / 2)
+		points[i].SetRandom()
+	}
+
+	// commitments
+	commitments, err := commitAll(benchScheme, ps)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	proof, err := benchScheme.BatchOpenMultiPoints(points, commitments, ps)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		benchScheme.BatchVerifyMultiPoints(commitments, &proof)
+	}
+}
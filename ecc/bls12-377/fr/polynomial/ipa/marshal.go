@@ -0,0 +1,255 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ipa
+
+import (
+	"io"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// WriteTo writes binary encoding of the scheme data.
+// It writes only the SRS.
+func (s *Scheme) WriteTo(w io.Writer) (int64, error) {
+	return s.SRS.WriteTo(w)
+}
+
+// ReadFrom decodes scheme data.
+// It reads only the SRS.
+func (s *Scheme) ReadFrom(r io.Reader) (int64, error) {
+	return s.SRS.ReadFrom(r)
+}
+
+// WriteTo writes binary encoding of the SRS
+func (srs *SRS) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&srs.U,
+		srs.G,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes SRS data from reader.
+func (srs *SRS) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&srs.U,
+		&srs.G,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of a Digest
+func (d *Digest) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+	err := enc.Encode((*bls12377.G1Affine)(d))
+	return enc.BytesWritten(), err
+}
+
+// ReadFrom decodes a Digest from reader
+func (d *Digest) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+	err := dec.Decode((*bls12377.G1Affine)(d))
+	return dec.BytesRead(), err
+}
+
+// Bytes returns the compressed binary encoding of a Digest
+func (d *Digest) Bytes() []byte {
+	b := (*bls12377.G1Affine)(d).Bytes()
+	return b[:]
+}
+
+// WriteTo writes binary encoding of a Proof
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&proof.Point,
+		&proof.ClaimedValue,
+		proof.L,
+		proof.R,
+		&proof.A,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes Proof data from reader.
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&proof.Point,
+		&proof.ClaimedValue,
+		&proof.L,
+		&proof.R,
+		&proof.A,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of a BatchProofsSinglePoint
+func (proof *BatchProofsSinglePoint) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&proof.Point,
+		uint64(len(proof.ClaimedValues)),
+	}
+	for i := 0; i < len(proof.ClaimedValues); i++ {
+		toEncode = append(toEncode, &proof.ClaimedValues[i])
+	}
+	toEncode = append(toEncode, proof.L, proof.R, &proof.A)
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes BatchProofsSinglePoint data from reader.
+func (proof *BatchProofsSinglePoint) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+
+	var nbClaimedValues uint64
+	toDecode := []interface{}{
+		&proof.Point,
+		&nbClaimedValues,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	proof.ClaimedValues = make([]fr.Element, nbClaimedValues)
+	toDecode = toDecode[:0]
+	for i := 0; i < len(proof.ClaimedValues); i++ {
+		toDecode = append(toDecode, &proof.ClaimedValues[i])
+	}
+	toDecode = append(toDecode, &proof.L, &proof.R, &proof.A)
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of a BatchProofsMultiPoints
+func (proof *BatchProofsMultiPoints) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12377.NewEncoder(w)
+
+	toEncode := []interface{}{
+		uint64(len(proof.Points)),
+	}
+	for i := 0; i < len(proof.Points); i++ {
+		toEncode = append(toEncode, &proof.Points[i])
+	}
+	toEncode = append(toEncode, uint64(len(proof.ClaimedValues)))
+	for i := 0; i < len(proof.ClaimedValues); i++ {
+		toEncode = append(toEncode, &proof.ClaimedValues[i])
+	}
+	toEncode = append(toEncode, &proof.W, proof.L, proof.R, &proof.A)
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes BatchProofsMultiPoints data from reader.
+func (proof *BatchProofsMultiPoints) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12377.NewDecoder(r)
+
+	var nbPoints uint64
+	if err := dec.Decode(&nbPoints); err != nil {
+		return dec.BytesRead(), err
+	}
+	proof.Points = make([]fr.Element, nbPoints)
+	for i := 0; i < len(proof.Points); i++ {
+		if err := dec.Decode(&proof.Points[i]); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	var nbClaimedValues uint64
+	if err := dec.Decode(&nbClaimedValues); err != nil {
+		return dec.BytesRead(), err
+	}
+	proof.ClaimedValues = make([]fr.Element, nbClaimedValues)
+	for i := 0; i < len(proof.ClaimedValues); i++ {
+		if err := dec.Decode(&proof.ClaimedValues[i]); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	toDecode := []interface{}{
+		&proof.W,
+		&proof.L,
+		&proof.R,
+		&proof.A,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
@@ -0,0 +1,273 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12377_pol "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	"github.com/consensys/gnark-crypto/polynomial"
+)
+
+// GenericScheme adapts Scheme to the curve agnostic polynomial.CommitmentScheme interface.
+//
+// Its methods check the types of their arguments at runtime, and panic when the opening
+// fails: the typed methods of Scheme, which return errors, should be preferred.
+type GenericScheme struct {
+	*Scheme
+}
+
+// Commit commits to a polynomial using a multi exponentiation with the SRS.
+// It is assumed that the polynomial is in canonical form, in Montgomery form.
+//
+// Commit panics if p is not a bls12377_pol.Polynomial or if
+// its size is larger than the SRS.
+func (s *GenericScheme) Commit(p polynomial.Polynomial) polynomial.Digest {
+	_p, ok := p.(bls12377_pol.Polynomial)
+	if !ok {
+		panic(ErrInvalidType)
+	}
+	res, err := s.Scheme.Commit(_p)
+	if err != nil {
+		panic(err)
+	}
+	return &res
+}
+
+// Open computes an opening proof of _p at _val.
+// Returns a *Proof.
+//
+// Open panics if the arguments do not have the expected types
+// (*fr.Element and bls12377_pol.Polynomial) or if the
+// size of p is larger than the SRS.
+func (s *GenericScheme) Open(_val interface{}, _p polynomial.Polynomial) polynomial.OpeningProof {
+	val, ok := _val.(*fr.Element)
+	if !ok {
+		panic(ErrInvalidType)
+	}
+	p, ok := _p.(bls12377_pol.Polynomial)
+	if !ok {
+		panic(ErrInvalidType)
+	}
+	res, err := s.Scheme.Open(val, p)
+	if err != nil {
+		panic(err)
+	}
+	return &res
+}
+
+// Verify verifies a KZG opening proof at a single point
+func (s *GenericScheme) Verify(point interface{}, commitment polynomial.Digest, proof polynomial.OpeningProof) error {
+	_point, ok := point.(*fr.Element)
+	if !ok {
+		return ErrInvalidType
+	}
+	_commitment, ok := commitment.(*Digest)
+	if !ok {
+		return ErrInvalidType
+	}
+	_proof, ok := proof.(*Proof)
+	if !ok {
+		return ErrInvalidType
+	}
+	if !_proof.Point.Equal(_point) {
+		return ErrVerifyOpeningProof
+	}
+	return s.Scheme.Verify(_commitment, _proof)
+}
+
+// BatchOpenSinglePoint creates a batch opening proof at _val of a list of polynomials.
+// It's an interactive protocol, made non interactive using Fiat Shamir.
+// point is the point at which the polynomials are opened (*fr.Element).
+// polynomials is the list of polynomials to open ([]polynomial.Polynomial).
+//
+// The polynomials are committed to so the challenge is bound to their digests.
+func (s *GenericScheme) BatchOpenSinglePoint(point interface{}, polynomials interface{}) polynomial.BatchOpeningProofSinglePoint {
+	_point, ok := point.(*fr.Element)
+	if !ok {
+		panic(ErrInvalidType)
+	}
+	_polynomials, err := toPolynomials(polynomials)
+	if err != nil {
+		panic(err)
+	}
+
+	digests := make([]Digest, len(_polynomials))
+	for i := 0; i < len(_polynomials); i++ {
+		digests[i], err = s.Scheme.Commit(_polynomials[i])
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	res, err := s.Scheme.BatchOpenSinglePoint(_point, digests, _polynomials)
+	if err != nil {
+		panic(err)
+	}
+	return &res
+}
+
+// BatchVerifySinglePoint verifies a batched opening proof at a single point of a list of polynomials.
+// point: point at which the polynomials are evaluated (*fr.Element)
+// claimedValues: claimed values of the polynomials at _val ([]fr.Element)
+// commitments: list of commitments to the polynomials which are opened ([]polynomial.Digest)
+// batchOpeningProof: the batched opening proof at a single point of the polynomials.
+func (s *GenericScheme) BatchVerifySinglePoint(
+	point interface{},
+	claimedValues interface{},
+	commitments interface{},
+	batchOpeningProof polynomial.BatchOpeningProofSinglePoint) error {
+
+	_point, ok := point.(*fr.Element)
+	if !ok {
+		return ErrInvalidType
+	}
+	_claimedValues, ok := claimedValues.([]fr.Element)
+	if !ok {
+		return ErrInvalidType
+	}
+	_proof, ok := batchOpeningProof.(*BatchProofsSinglePoint)
+	if !ok {
+		return ErrInvalidType
+	}
+	digests, err := toDigests(commitments)
+	if err != nil {
+		return err
+	}
+
+	// the proof must match the claims of the verifier
+	if !_proof.Point.Equal(_point) || len(_proof.ClaimedValues) != len(_claimedValues) {
+		return ErrVerifyBatchOpeningSinglePoint
+	}
+	for i := 0; i < len(_claimedValues); i++ {
+		if !_proof.ClaimedValues[i].Equal(&_claimedValues[i]) {
+			return ErrVerifyBatchOpeningSinglePoint
+		}
+	}
+
+	return s.Scheme.BatchVerifySinglePoint(digests, _proof)
+}
+
+// BatchOpenMultiPoints creates a batch opening proof of a list of polynomials, the i-th polynomial
+// being opened at the i-th point.
+// It's an interactive protocol, made non interactive using Fiat Shamir.
+// points is the list of points at which the polynomials are opened ([]fr.Element).
+// polynomials is the list of polynomials to open ([]polynomial.Polynomial).
+//
+// The polynomials are committed to so the challenges are bound to their digests.
+func (s *GenericScheme) BatchOpenMultiPoints(points interface{}, polynomials interface{}) polynomial.BatchOpeningProofMultiPoints {
+	_points, ok := points.([]fr.Element)
+	if !ok {
+		panic(ErrInvalidType)
+	}
+	_polynomials, err := toPolynomials(polynomials)
+	if err != nil {
+		panic(err)
+	}
+
+	digests := make([]Digest, len(_polynomials))
+	for i := 0; i < len(_polynomials); i++ {
+		digests[i], err = s.Scheme.Commit(_polynomials[i])
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	res, err := s.Scheme.BatchOpenMultiPoints(_points, digests, _polynomials)
+	if err != nil {
+		panic(err)
+	}
+	return &res
+}
+
+// BatchVerifyMultiPoints verifies a batched opening proof of a list of polynomials at multiple points.
+// points: points at which the polynomials are evaluated ([]fr.Element)
+// claimedValues: claimed values of the polynomials at their points ([]fr.Element)
+// commitments: list of commitments to the polynomials which are opened ([]polynomial.Digest)
+// batchOpeningProof: the batched opening proof at multiple points of the polynomials.
+func (s *GenericScheme) BatchVerifyMultiPoints(
+	points interface{},
+	claimedValues interface{},
+	commitments interface{},
+	batchOpeningProof polynomial.BatchOpeningProofMultiPoints) error {
+
+	_points, ok := points.([]fr.Element)
+	if !ok {
+		return ErrInvalidType
+	}
+	_claimedValues, ok := claimedValues.([]fr.Element)
+	if !ok {
+		return ErrInvalidType
+	}
+	_proof, ok := batchOpeningProof.(*BatchProofsMultiPoints)
+	if !ok {
+		return ErrInvalidType
+	}
+	digests, err := toDigests(commitments)
+	if err != nil {
+		return err
+	}
+
+	// the proof must match the claims of the verifier
+	if len(_proof.Points) != len(_points) || len(_proof.ClaimedValues) != len(_claimedValues) {
+		return ErrVerifyBatchOpeningMultiPoints
+	}
+	for i := 0; i < len(_points); i++ {
+		if !_proof.Points[i].Equal(&_points[i]) {
+			return ErrVerifyBatchOpeningMultiPoints
+		}
+	}
+	for i := 0; i < len(_claimedValues); i++ {
+		if !_proof.ClaimedValues[i].Equal(&_claimedValues[i]) {
+			return ErrVerifyBatchOpeningMultiPoints
+		}
+	}
+
+	return s.Scheme.BatchVerifyMultiPoints(digests, _proof)
+}
+
+// toPolynomials converts a []polynomial.Polynomial to a []bls12377_pol.Polynomial
+func toPolynomials(polynomials interface{}) ([]bls12377_pol.Polynomial, error) {
+	_polynomials, ok := polynomials.([]polynomial.Polynomial)
+	if !ok {
+		return nil, ErrInvalidType
+	}
+	res := make([]bls12377_pol.Polynomial, len(_polynomials))
+	for i := 0; i < len(_polynomials); i++ {
+		res[i], ok = _polynomials[i].(bls12377_pol.Polynomial)
+		if !ok {
+			return nil, ErrInvalidType
+		}
+	}
+	return res, nil
+}
+
+// toDigests converts a []polynomial.Digest to a []Digest
+func toDigests(digests interface{}) ([]Digest, error) {
+	_digests, ok := digests.([]polynomial.Digest)
+	if !ok {
+		return nil, ErrInvalidType
+	}
+	res := make([]Digest, len(_digests))
+	for i := 0; i < len(_digests); i++ {
+		d, ok := _digests[i].(*Digest)
+		if !ok {
+			return nil, ErrInvalidType
+		}
+		res[i] = *d
+	}
+	return res, nil
+}
@@ -0,0 +1,609 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"math/big"
+
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12377_pol "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidNbDigests              = errors.New("number of digests is not the same as the number of polynomials")
+	ErrInvalidNbPoints               = errors.New("number of points is not the same as the number of polynomials")
+	ErrInvalidPolynomialSize         = errors.New("invalid polynomial size (larger than SRS or == 0)")
+	ErrInvalidSRSSize                = errors.New("the size of the SRS must be at least 2")
+	ErrInvalidType                   = errors.New("the arguments do not have the types expected by the KZG scheme")
+	ErrVerifyOpeningProof            = errors.New("can't verify opening proof")
+	ErrVerifyBatchOpeningSinglePoint = errors.New("can't verify batch opening proof at single point")
+	ErrVerifyBatchOpeningMultiPoints = errors.New("can't verify batch opening proof at multiple points")
+)
+
+// Digest commitment of a polynomial.
+type Digest bls12377.G1Affine
+
+// Scheme stores KZG data
+type Scheme struct {
+	// SRS stores the result of the MPC
+	SRS SRS
+}
+
+// SRS stores the result of the MPC
+type SRS struct {
+	G1 []bls12377.G1Affine  // [gen, [alpha]gen, [alpha**2]gen, ...]
+	G2 [2]bls12377.G2Affine // [gen, [alpha]gen]
+}
+
+// Proof KZG proof for opening at a single point.
+type Proof struct {
+
+	// Point at which the polynomial is evaluated
+	Point fr.Element
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+
+	// H quotient polynomial (f - f(z))/(x-z)
+	H bls12377.G1Affine
+}
+
+// BatchProofsSinglePoint opening proof for many polynomials at the same point
+type BatchProofsSinglePoint struct {
+
+	// Point at which the polynomials are evaluated
+	Point fr.Element
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+
+	// H quotient polynomial Sum_i gamma**i*(f_i - f_i(z))/(x-z)
+	H bls12377.G1Affine
+}
+
+// BatchProofsMultiPoints opening proof for many polynomials, each one
+// at its own point.
+//
+// Following https://eprint.iacr.org/2020/081.pdf (SHPLONK), with gamma and z
+// two challenges, and (f_i, z_i, y_i) the opened polynomials, points and values,
+// the proof consists of two elements of G1: the commitment to
+// h(X) = Sum_i gamma**i*(f_i(X) - y_i)/(X - z_i),
+// and the commitment to the quotient of
+// L(X) = Sum_i gamma**i/(z - z_i)*(f_i(X) - y_i) - h(X) by X - z.
+type BatchProofsMultiPoints struct {
+
+	// Points at which the polynomials are evaluated
+	Points []fr.Element
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+
+	// W commitment to Sum_i gamma**i*(f_i(X) - y_i)/(X - z_i)
+	W bls12377.G1Affine
+
+	// WPrime commitment to L(X)/(X - z)
+	WPrime bls12377.G1Affine
+}
+
+// NewSRS returns a new SRS of the given size, using alpha as randomness source.
+//
+// In production, a SRS generated through MPC should be used.
+func NewSRS(size uint64, bAlpha *big.Int) (*SRS, error) {
+	if size < 2 {
+		return nil, ErrInvalidSRSSize
+	}
+
+	var srs SRS
+	srs.G1 = make([]bls12377.G1Affine, size)
+
+	var alpha fr.Element
+	alpha.SetBigInt(bAlpha)
+
+	_, _, gen1Aff, gen2Aff := bls12377.Generators()
+	srs.G1[0] = gen1Aff
+	srs.G2[0] = gen2Aff
+	srs.G2[1].ScalarMultiplication(&gen2Aff, bAlpha)
+
+	alphas := make([]fr.Element, size-1)
+	alphas[0] = alpha
+	for i := 1; i < len(alphas); i++ {
+		alphas[i].Mul(&alphas[i-1], &alpha)
+	}
+	for i := 0; i < len(alphas); i++ {
+		alphas[i].FromMont()
+	}
+	g1s := bls12377.BatchScalarMultiplicationG1(&gen1Aff, alphas)
+	copy(srs.G1[1:], g1s)
+
+	return &srs, nil
+}
+
+// NewScheme returns a new KZG scheme, with a SRS of the given size
+// derived from alpha.
+//
+// In production, a SRS generated through MPC should be used.
+func NewScheme(size uint64, alpha *big.Int) (*Scheme, error) {
+	srs, err := NewSRS(size, alpha)
+	if err != nil {
+		return nil, err
+	}
+	return &Scheme{SRS: *srs}, nil
+}
+
+// Commit commits to p using a multi exponentiation with the SRS.
+func (s *Scheme) Commit(p bls12377_pol.Polynomial) (Digest, error) {
+
+	if len(p) == 0 || len(p) > len(s.SRS.G1) {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+
+	// the multi exponentiation expects scalars in regular form
+	_p := make([]fr.Element, len(p))
+	parallel.Execute(len(p), func(start, end int) {
+		for i := start; i < end; i++ {
+			_p[i] = p[i]
+			_p[i].FromMont()
+		}
+	})
+
+	var res bls12377.G1Affine
+	res.MultiExp(s.SRS.G1[:len(p)], _p)
+
+	return Digest(res), nil
+}
+
+// Open computes an opening proof of p at point.
+func (s *Scheme) Open(point *fr.Element, p bls12377_pol.Polynomial) (Proof, error) {
+
+	if len(p) == 0 || len(p) > len(s.SRS.G1) {
+		return Proof{}, ErrInvalidPolynomialSize
+	}
+
+	// build the proof
+	res := Proof{
+		Point:        *point,
+		ClaimedValue: p.Evaluate(*point),
+	}
+
+	// compute H
+	h, _ := p.DivideByXMinusA(res.Point)
+
+	// commit to H
+	c, err := s.commitQuotient(h)
+	if err != nil {
+		return Proof{}, err
+	}
+	res.H.Set(&c)
+
+	return res, nil
+}
+
+// Verify verifies a KZG opening proof at a single point, that is it checks
+// e([f(alpha)]G1 - [f(a)]G1 + [a*H(alpha)]G1, G2) * e([-H(alpha)]G1, [alpha]G2) == 1
+func (s *Scheme) Verify(commitment *Digest, proof *Proof) error {
+
+	// [f(a)]G1
+	var claimedValueG1Aff bls12377.G1Affine
+	var claimedValueBigInt big.Int
+	proof.ClaimedValue.ToBigIntRegular(&claimedValueBigInt)
+	claimedValueG1Aff.ScalarMultiplication(&s.SRS.G1[0], &claimedValueBigInt)
+
+	// [a*H(alpha)]G1
+	var pointHG1Aff bls12377.G1Affine
+	var pointBigInt big.Int
+	proof.Point.ToBigIntRegular(&pointBigInt)
+	pointHG1Aff.ScalarMultiplication(&proof.H, &pointBigInt)
+
+	// [f(alpha) - f(a) + a*H(alpha)]G1
+	var totalG1Jac, tmpG1Jac bls12377.G1Jac
+	totalG1Jac.FromAffine((*bls12377.G1Affine)(commitment))
+	tmpG1Jac.FromAffine(&claimedValueG1Aff)
+	totalG1Jac.SubAssign(&tmpG1Jac)
+	tmpG1Jac.FromAffine(&pointHG1Aff)
+	totalG1Jac.AddAssign(&tmpG1Jac)
+	var totalG1Aff bls12377.G1Affine
+	totalG1Aff.FromJacobian(&totalG1Jac)
+
+	// [-H(alpha)]G1
+	var negH bls12377.G1Affine
+	negH.Neg(&proof.H)
+
+	// check the pairing equation
+	check, err := bls12377.PairingCheck(
+		[]bls12377.G1Affine{totalG1Aff, negH},
+		[]bls12377.G2Affine{s.SRS.G2[0], s.SRS.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// BatchOpenSinglePoint creates a batch opening proof at point of a list of polynomials.
+// The digests of the polynomials are bound to the Fiat Shamir challenge.
+func (s *Scheme) BatchOpenSinglePoint(point *fr.Element, digests []Digest, polynomials []bls12377_pol.Polynomial) (BatchProofsSinglePoint, error) {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(polynomials) {
+		return BatchProofsSinglePoint{}, ErrInvalidNbDigests
+	}
+
+	// compute the purported values
+	res := BatchProofsSinglePoint{Point: *point}
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	largestPoly := -1
+	for i := 0; i < len(polynomials); i++ {
+		if len(polynomials[i]) == 0 || len(polynomials[i]) > len(s.SRS.G1) {
+			return BatchProofsSinglePoint{}, ErrInvalidPolynomialSize
+		}
+		res.ClaimedValues[i] = polynomials[i].Evaluate(*point)
+		if len(polynomials[i]) > largestPoly {
+			largestPoly = len(polynomials[i])
+		}
+	}
+
+	// derive the challenge gamma, binded to the point and the commitments
+	gamma, err := deriveGamma(point, digests, res.ClaimedValues)
+	if err != nil {
+		return BatchProofsSinglePoint{}, err
+	}
+
+	// fold the claimed values and the polynomials
+	var foldedEvaluations fr.Element
+	foldedPolynomials := make(bls12377_pol.Polynomial, largestPoly)
+	var gammaI fr.Element
+	gammaI.SetOne()
+	for i := 0; i < len(polynomials); i++ {
+		var t fr.Element
+		for j := 0; j < len(polynomials[i]); j++ {
+			t.Mul(&polynomials[i][j], &gammaI)
+			foldedPolynomials[j].Add(&foldedPolynomials[j], &t)
+		}
+		t.Mul(&res.ClaimedValues[i], &gammaI)
+		foldedEvaluations.Add(&foldedEvaluations, &t)
+		gammaI.Mul(&gammaI, &gamma)
+	}
+
+	// compute H
+	h, _ := foldedPolynomials.DivideByXMinusA(res.Point)
+	c, err := s.commitQuotient(h)
+	if err != nil {
+		return BatchProofsSinglePoint{}, err
+	}
+	res.H.Set(&c)
+
+	return res, nil
+}
+
+// BatchVerifySinglePoint verifies a batched opening proof at a single point of a list of polynomials.
+func (s *Scheme) BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchProofsSinglePoint) error {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+
+	// derive the challenge gamma, binded to the point and the commitments
+	gamma, err := deriveGamma(&batchOpeningProof.Point, digests, batchOpeningProof.ClaimedValues)
+	if err != nil {
+		return err
+	}
+
+	// fold the claimed values and the digests
+	gammai := make([]fr.Element, nbDigests)
+	gammai[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+	var foldedEvaluations, t fr.Element
+	for i := 0; i < nbDigests; i++ {
+		t.Mul(&batchOpeningProof.ClaimedValues[i], &gammai[i])
+		foldedEvaluations.Add(&foldedEvaluations, &t)
+	}
+	foldedDigest := foldDigests(digests, gammai)
+
+	// create the folded opening proof and verify it
+	var foldedProof Proof
+	foldedProof.Point.Set(&batchOpeningProof.Point)
+	foldedProof.ClaimedValue.Set(&foldedEvaluations)
+	foldedProof.H.Set(&batchOpeningProof.H)
+	if err := s.Verify(&foldedDigest, &foldedProof); err != nil {
+		return ErrVerifyBatchOpeningSinglePoint
+	}
+
+	return nil
+}
+
+// BatchOpenMultiPoints creates a batch opening proof of a list of polynomials, the i-th polynomial
+// being opened at the i-th point.
+// The digests of the polynomials are bound to the Fiat Shamir challenges.
+func (s *Scheme) BatchOpenMultiPoints(points []fr.Element, digests []Digest, polynomials []bls12377_pol.Polynomial) (BatchProofsMultiPoints, error) {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(polynomials) {
+		return BatchProofsMultiPoints{}, ErrInvalidNbDigests
+	}
+	if len(points) != len(polynomials) {
+		return BatchProofsMultiPoints{}, ErrInvalidNbPoints
+	}
+
+	// compute the purported values
+	var res BatchProofsMultiPoints
+	res.Points = make([]fr.Element, len(points))
+	copy(res.Points, points)
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	largestPoly := -1
+	for i := 0; i < len(polynomials); i++ {
+		if len(polynomials[i]) == 0 || len(polynomials[i]) > len(s.SRS.G1) {
+			return BatchProofsMultiPoints{}, ErrInvalidPolynomialSize
+		}
+		res.ClaimedValues[i] = polynomials[i].Evaluate(points[i])
+		if len(polynomials[i]) > largestPoly {
+			largestPoly = len(polynomials[i])
+		}
+	}
+
+	// derive the challenge gamma, binded to the points, the commitments and the values
+	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, "gamma", "z")
+	gamma, err := deriveGammaMultiPoints(&fs, points, digests, res.ClaimedValues)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	// h = Sum_i gamma**i*(f_i - y_i)/(X - z_i)
+	h := make(bls12377_pol.Polynomial, largestPoly-1)
+	var gammaI, t fr.Element
+	gammaI.SetOne()
+	for i := 0; i < len(polynomials); i++ {
+		q, _ := polynomials[i].DivideByXMinusA(points[i])
+		for j := 0; j < len(q); j++ {
+			t.Mul(&q[j], &gammaI)
+			h[j].Add(&h[j], &t)
+		}
+		gammaI.Mul(&gammaI, &gamma)
+	}
+	res.W, err = s.commitQuotient(h)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	// derive the challenge z, binded to W
+	z, err := deriveZMultiPoints(&fs, &res.W)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	// c_i = gamma**i / (z - z_i)
+	c, err := computeMultiPointsCoefficients(gamma, z, points)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	// L = Sum_i c_i*(f_i - y_i) - h, which vanishes at z
+	l := make(bls12377_pol.Polynomial, largestPoly)
+	for i := 0; i < len(polynomials); i++ {
+		for j := 0; j < len(polynomials[i]); j++ {
+			t.Mul(&polynomials[i][j], &c[i])
+			l[j].Add(&l[j], &t)
+		}
+		t.Mul(&res.ClaimedValues[i], &c[i])
+		l[0].Sub(&l[0], &t)
+	}
+	for j := 0; j < len(h); j++ {
+		l[j].Sub(&l[j], &h[j])
+	}
+
+	// W' = [L/(X - z)], L vanishing at z
+	lz, _ := l.DivideByXMinusA(z)
+	res.WPrime, err = s.commitQuotient(lz)
+	if err != nil {
+		return BatchProofsMultiPoints{}, err
+	}
+
+	return res, nil
+}
+
+// BatchVerifyMultiPoints verifies a batched opening proof of a list of polynomials at multiple points.
+//
+// With c_i = gamma**i / (z - z_i), the verifier computes
+// F = Sum_i c_i*[f_i] - W, and checks that W' is a valid opening proof of F at z
+// for the value Sum_i c_i*y_i.
+func (s *Scheme) BatchVerifyMultiPoints(digests []Digest, batchOpeningProof *BatchProofsMultiPoints) error {
+
+	nbDigests := len(digests)
+	if nbDigests == 0 || nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	if len(batchOpeningProof.Points) != nbDigests {
+		return ErrInvalidNbPoints
+	}
+
+	// derive the challenges gamma and z
+	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, "gamma", "z")
+	gamma, err := deriveGammaMultiPoints(&fs, batchOpeningProof.Points, digests, batchOpeningProof.ClaimedValues)
+	if err != nil {
+		return err
+	}
+	z, err := deriveZMultiPoints(&fs, &batchOpeningProof.W)
+	if err != nil {
+		return err
+	}
+
+	// c_i = gamma**i / (z - z_i)
+	c, err := computeMultiPointsCoefficients(gamma, z, batchOpeningProof.Points)
+	if err != nil {
+		return ErrVerifyBatchOpeningMultiPoints
+	}
+
+	// fold the claimed values: Sum_i c_i*y_i
+	var foldedEvaluations, t fr.Element
+	for i := 0; i < nbDigests; i++ {
+		t.Mul(&batchOpeningProof.ClaimedValues[i], &c[i])
+		foldedEvaluations.Add(&foldedEvaluations, &t)
+	}
+
+	// fold the digests: Sum_i c_i*[f_i] - W
+	foldedDigest := foldDigests(digests, c)
+	var foldedDigestJac, wJac bls12377.G1Jac
+	foldedDigestJac.FromAffine((*bls12377.G1Affine)(&foldedDigest))
+	wJac.FromAffine(&batchOpeningProof.W)
+	foldedDigestJac.SubAssign(&wJac)
+	(*bls12377.G1Affine)(&foldedDigest).FromJacobian(&foldedDigestJac)
+
+	// verify the opening proof of the folded digest at z
+	var foldedProof Proof
+	foldedProof.Point.Set(&z)
+	foldedProof.ClaimedValue.Set(&foldedEvaluations)
+	foldedProof.H.Set(&batchOpeningProof.WPrime)
+	if err := s.Verify(&foldedDigest, &foldedProof); err != nil {
+		return ErrVerifyBatchOpeningMultiPoints
+	}
+
+	return nil
+}
+
+// computeMultiPointsCoefficients returns gamma**i / (z - points[i])
+func computeMultiPointsCoefficients(gamma, z fr.Element, points []fr.Element) ([]fr.Element, error) {
+	res := make([]fr.Element, len(points))
+	for i := 0; i < len(points); i++ {
+		res[i].Sub(&z, &points[i])
+		if res[i].IsZero() {
+			return nil, ErrVerifyBatchOpeningMultiPoints
+		}
+	}
+	res = bls12377_pol.BatchInvert(res)
+	var gammaI fr.Element
+	gammaI.SetOne()
+	for i := 0; i < len(res); i++ {
+		res[i].Mul(&res[i], &gammaI)
+		gammaI.Mul(&gammaI, &gamma)
+	}
+	return res, nil
+}
+
+// deriveGammaMultiPoints derives the challenge gamma of the multi points batch opening,
+// binded to the points, the digests and the claimed values.
+func deriveGammaMultiPoints(fs *fiatshamir.Transcript, points []fr.Element, digests []Digest, claimedValues []fr.Element) (fr.Element, error) {
+	for i := 0; i < len(points); i++ {
+		b := points[i].Bytes()
+		if err := fs.Bind("gamma", b[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := 0; i < len(digests); i++ {
+		if err := fs.Bind("gamma", digests[i].Bytes()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := 0; i < len(claimedValues); i++ {
+		b := claimedValues[i].Bytes()
+		if err := fs.Bind("gamma", b[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	gammaByte, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var gamma fr.Element
+	gamma.SetBytes(gammaByte)
+
+	return gamma, nil
+}
+
+// deriveZMultiPoints derives the challenge z of the multi points batch opening, binded to W.
+func deriveZMultiPoints(fs *fiatshamir.Transcript, w *bls12377.G1Affine) (fr.Element, error) {
+	b := w.Bytes()
+	if err := fs.Bind("z", b[:]); err != nil {
+		return fr.Element{}, err
+	}
+	zByte, err := fs.ComputeChallenge("z")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var z fr.Element
+	z.SetBytes(zByte)
+
+	return z, nil
+}
+
+// commitQuotient commits to a quotient polynomial, which may be empty
+// when the divided polynomial was a constant.
+func (s *Scheme) commitQuotient(h bls12377_pol.Polynomial) (bls12377.G1Affine, error) {
+	if len(h) == 0 {
+		return bls12377.G1Affine{}, nil
+	}
+	c, err := s.Commit(h)
+	if err != nil {
+		return bls12377.G1Affine{}, err
+	}
+	return bls12377.G1Affine(c), nil
+}
+
+// foldDigests computes Sum_i scalars[i]*digests[i], scalars being in Montgomery form.
+func foldDigests(digests []Digest, scalars []fr.Element) Digest {
+	points := make([]bls12377.G1Affine, len(digests))
+	_scalars := make([]fr.Element, len(scalars))
+	for i := 0; i < len(digests); i++ {
+		points[i] = bls12377.G1Affine(digests[i])
+		_scalars[i] = scalars[i]
+		_scalars[i].FromMont()
+	}
+	var res bls12377.G1Affine
+	res.MultiExp(points, _scalars)
+	return Digest(res)
+}
+
+// deriveGamma derives the challenge used to fold the polynomials opened at a single point,
+// binded to the point, the digests and the claimed values.
+func deriveGamma(point *fr.Element, digests []Digest, claimedValues []fr.Element) (fr.Element, error) {
+
+	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, "gamma")
+
+	b := point.Bytes()
+	if err := fs.Bind("gamma", b[:]); err != nil {
+		return fr.Element{}, err
+	}
+	for i := 0; i < len(digests); i++ {
+		if err := fs.Bind("gamma", digests[i].Bytes()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := 0; i < len(claimedValues); i++ {
+		b = claimedValues[i].Bytes()
+		if err := fs.Bind("gamma", b[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	gammaByte, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var gamma fr.Element
+	gamma.SetBytes(gammaByte)
+
+	return gamma, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+)
+
+var (
+	ErrPolynomialTooLarge   = errors.New("the size of the polynomial is larger than the size of the domain")
+	ErrInvalidNbEvaluations = errors.New("the number of evaluations is not the size of the domain")
+	ErrNotDivisible         = errors.New("the polynomial is not divisible by the vanishing polynomial")
+)
+
+// LagrangePolynomial polynomial represented by its evaluations on a fft.Domain, that is by
+// its coefficients in the Lagrange basis of the domain.
+// Evaluations[i] is the evaluation at Domain.Generator**i (natural order).
+type LagrangePolynomial struct {
+	Domain      *fft.Domain
+	Evaluations []fr.Element
+}
+
+// NewLagrangePolynomial returns the polynomial of size domain.Cardinality taking the values
+// evaluations on the domain, in natural order. The evaluations are not copied.
+func NewLagrangePolynomial(domain *fft.Domain, evaluations []fr.Element) (*LagrangePolynomial, error) {
+	if uint64(len(evaluations)) != domain.Cardinality {
+		return nil, ErrInvalidNbEvaluations
+	}
+	return &LagrangePolynomial{Domain: domain, Evaluations: evaluations}, nil
+}
+
+// ToLagrange returns the evaluations of p on the domain, computed with a FFT.
+// The size of p must be at most the size of the domain.
+func (p Polynomial) ToLagrange(domain *fft.Domain) (*LagrangePolynomial, error) {
+	if uint64(len(p)) > domain.Cardinality {
+		return nil, ErrPolynomialTooLarge
+	}
+	evaluations := make([]fr.Element, domain.Cardinality)
+	copy(evaluations, p)
+	domain.FFT(evaluations, fft.DIF, 0)
+	fft.BitReverse(evaluations)
+	return &LagrangePolynomial{Domain: domain, Evaluations: evaluations}, nil
+}
+
+// ToCanonical returns the coefficients of p in the canonical basis, computed with an inverse FFT
+func (p *LagrangePolynomial) ToCanonical() Polynomial {
+	res := make(Polynomial, len(p.Evaluations))
+	copy(res, p.Evaluations)
+	p.Domain.FFTInverse(res, fft.DIF, 0)
+	fft.BitReverse(res)
+	return res
+}
+
+// Evaluate evaluates p at x with the barycentric formula
+// p(x) = (x**n - 1)/n * Sum_i w**i*p(w**i)/(x - w**i)
+// using O(n) multiplications and a single inversion, without going back to the canonical basis.
+func (p *LagrangePolynomial) Evaluate(x fr.Element) fr.Element {
+	n := len(p.Evaluations)
+
+	// x - w**i
+	denominators := make([]fr.Element, n)
+	roots := make([]fr.Element, n)
+	roots[0].SetOne()
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			roots[i].Mul(&roots[i-1], &p.Domain.Generator)
+		}
+		denominators[i].Sub(&x, &roots[i])
+		if denominators[i].IsZero() {
+			// x = w**i belongs to the domain
+			return p.Evaluations[i]
+		}
+	}
+	denominators = BatchInvert(denominators)
+
+	var res, t fr.Element
+	for i := 0; i < n; i++ {
+		t.Mul(&roots[i], &denominators[i]).Mul(&t, &p.Evaluations[i])
+		res.Add(&res, &t)
+	}
+	t = EvaluateVanishing(p.Domain, x)
+	res.Mul(&res, &t).Mul(&res, &p.Domain.CardinalityInv)
+
+	return res
+}
+
+// EvaluateVanishing returns Z(x) = x**n - 1, Z being the vanishing polynomial of the
+// domain of size n
+func EvaluateVanishing(domain *fft.Domain, x fr.Element) fr.Element {
+	res := x
+	for i := 0; i < bits.TrailingZeros64(domain.Cardinality); i++ {
+		res.Square(&res)
+	}
+	one := fr.One()
+	res.Sub(&res, &one)
+	return res
+}
+
+// EvaluateFirstLagrange returns L_0(x) = (x**n - 1)/(n*(x - 1)), L_0 being the first
+// polynomial of the Lagrange basis of the domain of size n (1 at 1, 0 elsewhere on the domain)
+func EvaluateFirstLagrange(domain *fft.Domain, x fr.Element) fr.Element {
+	one := fr.One()
+	if x.Equal(&one) {
+		return one
+	}
+	var den fr.Element
+	den.Sub(&x, &one).Inverse(&den)
+	res := EvaluateVanishing(domain, x)
+	res.Mul(&res, &den).Mul(&res, &domain.CardinalityInv)
+	return res
+}
+
+// DivideByVanishing returns p/Z, Z = X**n - 1 being the vanishing polynomial of the domain
+// of size n. p must be divisible by Z, as the constraint polynomials of a prover which are
+// zero on the domain, ErrNotDivisible is returned otherwise.
+//
+// The division is done pointwise, on a coset of a domain large enough to represent p, where
+// Z doesn't vanish. The result interpolates p/Z on the coset: it has a degree less than
+// len(p) - n if and only if p is divisible by Z, which is checked at no extra cost.
+func (p Polynomial) DivideByVanishing(domain *fft.Domain) (Polynomial, error) {
+	n := domain.Cardinality
+	if uint64(len(p)) <= n {
+		if !p.IsZero() {
+			return nil, ErrNotDivisible
+		}
+		return Polynomial{}, nil
+	}
+
+	// evaluations of p on the coset g*<w> of the larger domain, g being the FinerGenerator
+	// of order 2N, in natural order
+	largeDomain, err := fft.NewDomainE(uint64(len(p)), 1, false)
+	if err != nil {
+		return nil, err
+	}
+	a := make([]fr.Element, largeDomain.Cardinality)
+	copy(a, p)
+	largeDomain.FFT(a, fft.DIF, 1)
+	fft.BitReverse(a)
+
+	// Z(g*w**i) = g**n*(w**n)**i - 1 takes N/n distinct values, none being zero
+	// since g**n is not in <w**n>
+	ratio := largeDomain.Cardinality / n
+	bn := new(big.Int).SetUint64(n)
+	var gn, wn fr.Element
+	gn.Exp(largeDomain.FinerGenerator, bn)
+	wn.Exp(largeDomain.Generator, bn)
+	zInv := make([]fr.Element, ratio)
+	zInv[0] = gn
+	for i := uint64(1); i < ratio; i++ {
+		zInv[i].Mul(&zInv[i-1], &wn)
+	}
+	one := fr.One()
+	for i := uint64(0); i < ratio; i++ {
+		zInv[i].Sub(&zInv[i], &one)
+	}
+	zInv = BatchInvert(zInv)
+
+	for i := uint64(0); i < largeDomain.Cardinality; i++ {
+		a[i].Mul(&a[i], &zInv[i%ratio])
+	}
+	largeDomain.FFTInverse(a, fft.DIF, 1)
+	fft.BitReverse(a)
+
+	// q = a[:len(p)-n] is the quotient iff the coefficients above are zero: q*Z and p then
+	// have a degree less than N and agree on the N points of the coset
+	if !Polynomial(a[uint64(len(p))-n:]).IsZero() {
+		return nil, ErrNotDivisible
+	}
+
+	return a[:uint64(len(p))-n], nil
+}
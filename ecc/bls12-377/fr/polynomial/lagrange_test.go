@@ -0,0 +1,155 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+)
+
+func TestLagrangeConversions(t *testing.T) {
+
+	domain := fft.NewDomain(32, 0, false)
+	p := randomPolynomial(20)
+
+	lp, err := p.ToLagrange(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the evaluations are in natural order
+	var x fr.Element
+	x.SetOne()
+	for i := 0; i < len(lp.Evaluations); i++ {
+		if expected := p.Evaluate(x); !lp.Evaluations[i].Equal(&expected) {
+			t.Fatal("wrong evaluation on the domain")
+		}
+		x.Mul(&x, &domain.Generator)
+	}
+
+	if !lp.ToCanonical().Equal(p) {
+		t.Fatal("the conversions to and from the Lagrange basis should be inverses")
+	}
+
+	if _, err := randomPolynomial(33).ToLagrange(domain); err != ErrPolynomialTooLarge {
+		t.Fatal("a polynomial larger than the domain should be rejected")
+	}
+	if _, err := NewLagrangePolynomial(domain, make([]fr.Element, 31)); err != ErrInvalidNbEvaluations {
+		t.Fatal("a wrong number of evaluations should be rejected")
+	}
+}
+
+func TestLagrangeEvaluate(t *testing.T) {
+
+	domain := fft.NewDomain(64, 0, false)
+	lp, err := NewLagrangePolynomial(domain, randomPoints(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := lp.ToCanonical()
+
+	// outside of the domain
+	var x fr.Element
+	x.SetRandom()
+	if y, expected := lp.Evaluate(x), p.Evaluate(x); !y.Equal(&expected) {
+		t.Fatal("wrong barycentric evaluation")
+	}
+
+	// on the domain
+	x.Square(&domain.Generator)
+	if y := lp.Evaluate(x); !y.Equal(&lp.Evaluations[2]) {
+		t.Fatal("wrong evaluation on the domain")
+	}
+}
+
+func TestVanishingAndFirstLagrange(t *testing.T) {
+
+	domain := fft.NewDomain(16, 0, false)
+	one := fr.One()
+
+	// Z is zero on the domain, L_0 is 1 at 1 and 0 elsewhere
+	x := one
+	for i := 0; i < 16; i++ {
+		if z := EvaluateVanishing(domain, x); !z.IsZero() {
+			t.Fatal("the vanishing polynomial should be zero on the domain")
+		}
+		l0 := EvaluateFirstLagrange(domain, x)
+		if (i == 0 && !l0.Equal(&one)) || (i != 0 && !l0.IsZero()) {
+			t.Fatal("wrong first Lagrange polynomial on the domain")
+		}
+		x.Mul(&x, &domain.Generator)
+	}
+
+	// outside of the domain, compare with the evaluation of L_0 in the Lagrange basis
+	evaluations := make([]fr.Element, 16)
+	evaluations[0].SetOne()
+	l0, err := NewLagrangePolynomial(domain, evaluations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.SetRandom()
+	if y, expected := EvaluateFirstLagrange(domain, x), l0.ToCanonical().Evaluate(x); !y.Equal(&expected) {
+		t.Fatal("wrong first Lagrange polynomial")
+	}
+	var expected fr.Element
+	expected.Exp(x, big.NewInt(16)).Sub(&expected, &one)
+	if y := EvaluateVanishing(domain, x); !y.Equal(&expected) {
+		t.Fatal("wrong vanishing polynomial")
+	}
+}
+
+func TestDivideByVanishing(t *testing.T) {
+
+	domain := fft.NewDomain(16, 0, false)
+
+	// Z = X**16 - 1
+	z := make(Polynomial, 17)
+	z[0].SetOne()
+	z[0].Neg(&z[0])
+	z[16].SetOne()
+
+	for _, size := range []int{1, 17, 50} {
+		q := randomPolynomial(size)
+		var p Polynomial
+		p.Mul(q, z)
+
+		res, err := p.DivideByVanishing(domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Equal(q) {
+			t.Fatalf("wrong division by the vanishing polynomial of a quotient of size %d", size)
+		}
+
+		// p + 1 is not divisible by Z
+		p[0].Add(&p[0], &z[16])
+		if _, err := p.DivideByVanishing(domain); err != ErrNotDivisible {
+			t.Fatalf("a polynomial of size %d not divisible by Z should be rejected", len(p))
+		}
+	}
+
+	// polynomials smaller than Z
+	if res, err := (Polynomial{}).DivideByVanishing(domain); err != nil || len(res) != 0 {
+		t.Fatal("the quotient of the zero polynomial should be zero")
+	}
+	if _, err := randomPolynomial(16).DivideByVanishing(domain); err != ErrNotDivisible {
+		t.Fatal("a non zero polynomial smaller than Z should be rejected")
+	}
+}
@@ -72,3 +72,19 @@ func (s *Scheme) BatchVerifySinglePoint(
 	return nil
 
 }
+
+// BatchOpenMultiPoints computes a batch opening proof for the polynomials at their respective points.
+func (s *Scheme) BatchOpenMultiPoints(points interface{}, polynomials interface{}) polynomial.BatchOpeningProofMultiPoints {
+	return &MockProof{}
+}
+
+// BatchVerifyMultiPoints mock implementation of the verification of a batch opening proof at multiple points
+func (s *Scheme) BatchVerifyMultiPoints(
+	points interface{},
+	claimedValues interface{},
+	commitments interface{},
+	batchOpeningProof polynomial.BatchOpeningProofMultiPoints) error {
+
+	return nil
+
+}
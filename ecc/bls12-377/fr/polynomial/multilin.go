@@ -0,0 +1,123 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+var ErrInvalidNbVariables = errors.New("the number of coordinates is not the number of variables of the multilinear polynomial")
+
+// MultiLin dense multilinear polynomial in n variables X_1, ..., X_n, represented by
+// its 2**n evaluations on the boolean hypercube {0, 1}**n.
+//
+// The evaluation at (b_1, ..., b_n) is stored at the index whose binary decomposition
+// is b_1 b_2 ... b_n, b_1 being the most significant bit: the first half of the
+// evaluations is at X_1 = 0, the second half at X_1 = 1.
+type MultiLin []fr.Element
+
+// NbVars returns the number of variables of m, len(m) must be a power of 2
+func (m MultiLin) NbVars() int {
+	return bits.TrailingZeros(uint(len(m)))
+}
+
+// Clone returns a copy of m
+func (m MultiLin) Clone() MultiLin {
+	res := make(MultiLin, len(m))
+	copy(res, m)
+	return res
+}
+
+// Fold fixes in place the first variable of m to r, m(X_1, X_2, ..., X_n) becoming
+// m(r, X_2, ..., X_n). The number of evaluations is halved, m must have at least one variable.
+func (m *MultiLin) Fold(r fr.Element) {
+	mid := len(*m) / 2
+	bottom, top := (*m)[:mid], (*m)[mid:]
+
+	// m(r, ...) = m(0, ...) + r*(m(1, ...) - m(0, ...))
+	var t fr.Element
+	for i := 0; i < mid; i++ {
+		t.Sub(&top[i], &bottom[i])
+		t.Mul(&t, &r)
+		bottom[i].Add(&bottom[i], &t)
+	}
+
+	*m = (*m)[:mid]
+}
+
+// PartialEvaluate returns m(r_1, ..., r_k, X_{k+1}, ..., X_n), r having k <= n coordinates
+func (m MultiLin) PartialEvaluate(r []fr.Element) (MultiLin, error) {
+	if len(r) > m.NbVars() {
+		return nil, ErrInvalidNbVariables
+	}
+	res := m.Clone()
+	for i := 0; i < len(r); i++ {
+		res.Fold(r[i])
+	}
+	return res, nil
+}
+
+// Evaluate returns m(r_1, ..., r_n), r having exactly n coordinates
+func (m MultiLin) Evaluate(r []fr.Element) (fr.Element, error) {
+	if len(r) != m.NbVars() {
+		return fr.Element{}, ErrInvalidNbVariables
+	}
+	res, err := m.PartialEvaluate(r)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	return res[0], nil
+}
+
+// EqTable returns the evaluations on the boolean hypercube of the multilinear polynomial
+// eq(r, X) = Prod_i (r_i*X_i + (1 - r_i)*(1 - X_i)), which is 1 at X = r when r is boolean and
+// 0 elsewhere on the hypercube. For m a multilinear polynomial, m(r) = Sum_b eq(r, b)*m(b).
+func EqTable(r []fr.Element) MultiLin {
+	res := make(MultiLin, 1<<len(r))
+	res[0].SetOne()
+
+	// after the i-th step, the first 2**(i+1) entries are the table of eq(r_1..r_{i+1}, .)
+	for i := 0; i < len(r); i++ {
+		for j := (1 << i) - 1; j >= 0; j-- {
+			// X_{i+1} is the least significant bit so far
+			res[2*j+1].Mul(&res[j], &r[i])
+			res[2*j].Sub(&res[j], &res[2*j+1])
+		}
+	}
+
+	return res
+}
+
+// EvaluateEq returns eq(x, y) = Prod_i (x_i*y_i + (1 - x_i)*(1 - y_i))
+func EvaluateEq(x, y []fr.Element) (fr.Element, error) {
+	if len(x) != len(y) {
+		return fr.Element{}, ErrInvalidNbVariables
+	}
+	res := fr.One()
+	var t, u fr.Element
+	for i := 0; i < len(x); i++ {
+		// x_i*y_i + (1 - x_i)*(1 - y_i) = 1 - x_i - y_i + 2*x_i*y_i
+		t.Mul(&x[i], &y[i]).Double(&t)
+		u.SetOne().Sub(&u, &x[i]).Sub(&u, &y[i])
+		t.Add(&t, &u)
+		res.Mul(&res, &t)
+	}
+	return res, nil
+}
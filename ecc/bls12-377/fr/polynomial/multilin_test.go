@@ -0,0 +1,127 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func TestMultiLinEvaluate(t *testing.T) {
+
+	// m(X_1, X_2) = 1 + 2*X_1 + 3*X_2 + 4*X_1*X_2, evaluations at 00, 01, 10, 11
+	var m MultiLin = make([]fr.Element, 4)
+	m[0].SetUint64(1)
+	m[1].SetUint64(4)
+	m[2].SetUint64(3)
+	m[3].SetUint64(10)
+	if m.NbVars() != 2 {
+		t.Fatal("wrong number of variables")
+	}
+
+	r := randomPoints(2)
+	var expected, t1 fr.Element
+	expected.SetUint64(1)
+	t1.SetUint64(2)
+	t1.Mul(&t1, &r[0])
+	expected.Add(&expected, &t1)
+	t1.SetUint64(3)
+	t1.Mul(&t1, &r[1])
+	expected.Add(&expected, &t1)
+	t1.SetUint64(4)
+	t1.Mul(&t1, &r[0]).Mul(&t1, &r[1])
+	expected.Add(&expected, &t1)
+
+	y, err := m.Evaluate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !y.Equal(&expected) {
+		t.Fatal("wrong evaluation")
+	}
+	if len(m) != 4 {
+		t.Fatal("Evaluate should not modify the polynomial")
+	}
+
+	if _, err := m.Evaluate(r[:1]); err != ErrInvalidNbVariables {
+		t.Fatal("an evaluation with a wrong number of coordinates should fail")
+	}
+}
+
+func TestMultiLinPartialEvaluate(t *testing.T) {
+
+	m := MultiLin(randomPoints(1 << 5))
+	r := randomPoints(5)
+
+	expected, err := m.Evaluate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	partial, err := m.PartialEvaluate(r[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partial.NbVars() != 3 {
+		t.Fatal("wrong number of variables after the partial evaluation")
+	}
+	y, err := partial.Evaluate(r[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !y.Equal(&expected) {
+		t.Fatal("wrong partial evaluation")
+	}
+
+	// on the hypercube, the evaluations are the entries of m: 0b10110 = 22
+	var zero, one fr.Element
+	one.SetOne()
+	if y, _ := m.Evaluate([]fr.Element{one, zero, one, one, zero}); !y.Equal(&m[22]) {
+		t.Fatal("wrong order of the variables")
+	}
+}
+
+func TestEqTable(t *testing.T) {
+
+	m := MultiLin(randomPoints(1 << 6))
+	r := randomPoints(6)
+
+	// m(r) = Sum_b eq(r, b)*m(b)
+	eq := EqTable(r)
+	var sum, t1 fr.Element
+	for i := 0; i < len(m); i++ {
+		t1.Mul(&eq[i], &m[i])
+		sum.Add(&sum, &t1)
+	}
+	expected, err := m.Evaluate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sum.Equal(&expected) {
+		t.Fatal("wrong eq table")
+	}
+
+	// eq(r, .) is multilinear, its evaluation at x is eq(r, x)
+	x := randomPoints(6)
+	y, err := eq.Evaluate(x)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected, _ := EvaluateEq(r, x); !y.Equal(&expected) {
+		t.Fatal("wrong evaluation of eq")
+	}
+}
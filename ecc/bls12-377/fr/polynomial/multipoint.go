@@ -0,0 +1,239 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidNbPoints = errors.New("the number of points and values must be the same, and positive")
+	ErrDuplicatePoint  = errors.New("the interpolation points must be distinct")
+)
+
+// MultiEvaluate returns the evaluations of p at the points.
+//
+// The evaluations are computed with a subproduct tree in O(n log**2 n) operations,
+// n being the largest of len(p) and len(points), instead of O(n**2) with Evaluate.
+func (p Polynomial) MultiEvaluate(points []fr.Element) []fr.Element {
+	if len(points) == 0 {
+		return []fr.Element{}
+	}
+	return buildSubproductTree(points).evaluate(p)
+}
+
+// Interpolate returns the polynomial of size len(xs) such that p(xs[i]) = ys[i].
+//
+// The interpolation is done with a subproduct tree in O(n log**2 n) operations.
+// It returns ErrDuplicatePoint if the xs are not distinct.
+func Interpolate(xs, ys []fr.Element) (Polynomial, error) {
+	if len(xs) == 0 || len(xs) != len(ys) {
+		return nil, ErrInvalidNbPoints
+	}
+	tree := buildSubproductTree(xs)
+
+	// with m = Prod_i (X - xs[i]), p = Sum_i ys[i]/m'(xs[i]) * m/(X - xs[i])
+	weights := tree.evaluate(tree.root().Derivative())
+	for i := 0; i < len(weights); i++ {
+		if weights[i].IsZero() {
+			return nil, ErrDuplicatePoint
+		}
+	}
+	weights = BatchInvert(weights)
+	for i := 0; i < len(weights); i++ {
+		weights[i].Mul(&weights[i], &ys[i])
+	}
+
+	return tree.combine(weights), nil
+}
+
+// subproductTree stores the products of the X - x_i two by two: the leaves (level 0) are
+// the X - x_i, and each node of level k+1 is the product of two nodes of level k, the last
+// node of a level with an odd number of nodes being moved up as is.
+type subproductTree [][]Polynomial
+
+func buildSubproductTree(points []fr.Element) subproductTree {
+	level := make([]Polynomial, len(points))
+	for i := 0; i < len(points); i++ {
+		level[i] = make(Polynomial, 2)
+		level[i][0].Neg(&points[i])
+		level[i][1].SetOne()
+	}
+	tree := subproductTree{level}
+
+	for len(level) > 1 {
+		next := make([]Polynomial, (len(level)+1)/2)
+		parallel.Execute(len(level)/2, func(start, end int) {
+			for i := start; i < end; i++ {
+				next[i].Mul(level[2*i], level[2*i+1])
+			}
+		})
+		if len(level)%2 == 1 {
+			next[len(next)-1] = level[len(level)-1]
+		}
+		tree = append(tree, next)
+		level = next
+	}
+
+	return tree
+}
+
+// root returns Prod_i (X - x_i)
+func (tree subproductTree) root() Polynomial {
+	return tree[len(tree)-1][0]
+}
+
+// evaluate returns the p(x_i), computed going down the tree with p mod node, until the
+// leaves where p mod (X - x_i) = p(x_i)
+func (tree subproductTree) evaluate(p Polynomial) []fr.Element {
+	remainders := []Polynomial{rem(p, tree.root())}
+	for k := len(tree) - 2; k >= 0; k-- {
+		level := tree[k]
+		next := make([]Polynomial, len(level))
+		parallel.Execute(len(level), func(start, end int) {
+			for i := start; i < end; i++ {
+				next[i] = rem(remainders[i/2], level[i])
+			}
+		})
+		remainders = next
+	}
+
+	res := make([]fr.Element, len(remainders))
+	for i := 0; i < len(remainders); i++ {
+		if len(remainders[i]) > 0 {
+			res[i] = remainders[i][0]
+		}
+	}
+	return res
+}
+
+// combine returns Sum_i c_i * Prod_{j != i} (X - x_j), computed going up the tree
+func (tree subproductTree) combine(c []fr.Element) Polynomial {
+	sums := make([]Polynomial, len(c))
+	for i := 0; i < len(c); i++ {
+		sums[i] = Polynomial{c[i]}
+	}
+
+	for k := 0; k < len(tree)-1; k++ {
+		level := tree[k]
+		next := make([]Polynomial, len(tree[k+1]))
+		parallel.Execute(len(level)/2, func(start, end int) {
+			var t Polynomial
+			for i := start; i < end; i++ {
+				next[i].Mul(sums[2*i], level[2*i+1])
+				t.Mul(sums[2*i+1], level[2*i])
+				next[i].Add(next[i], t)
+			}
+		})
+		if len(level)%2 == 1 {
+			next[len(next)-1] = sums[len(sums)-1]
+		}
+		sums = next
+	}
+
+	res := make(Polynomial, len(c))
+	copy(res, sums[0])
+	return res
+}
+
+// rem returns a mod b, b being monic. The quotient is computed with a Newton iteration
+// when the operands are large, and with the schoolbook division otherwise.
+func rem(a, b Polynomial) Polynomial {
+	if len(a) < len(b) {
+		return a
+	}
+	n := len(a) - len(b) + 1
+	if n < mulFFTThreshold || len(b) < mulFFTThreshold {
+		_, r, _ := a.Div(b)
+		return r
+	}
+
+	return remNewton(a, b, invModXn(reverse(b), n))
+}
+
+// remNewton returns a mod b, bRevInv being the inverse of the reverse of b modulo X**k
+// for some k >= len(a) - len(b) + 1
+func remNewton(a, b, bRevInv Polynomial) Polynomial {
+	n := len(a) - len(b) + 1
+
+	// the reversed quotient is rev(a)/rev(b) mod X**n
+	var q Polynomial
+	q.Mul(truncate(bRevInv, n), reverse(a)[:n])
+	q = reverse(q[:n])
+
+	var r Polynomial
+	r.Mul(q, b)
+	r.Sub(a[:len(b)-1], r[:len(b)-1])
+	return r.trim()
+}
+
+// invModXn returns the inverse of f modulo X**n with a Newton iteration:
+// g_{2k} = g_k*(2 - f*g_k) mod X**(2k). f[0] must be non zero.
+func invModXn(f Polynomial, n int) Polynomial {
+	g := make(Polynomial, 1, n)
+	g[0].Inverse(&f[0])
+
+	var two fr.Element
+	two.SetUint64(2)
+	for k := 1; k < n; {
+		k = 2 * k
+		if k > n {
+			k = n
+		}
+		var e Polynomial
+		e.Mul(truncate(f, k), g)
+		e = resizeZero(e, k)
+		for i := 0; i < k; i++ {
+			e[i].Neg(&e[i])
+		}
+		e[0].Add(&e[0], &two)
+		g.Mul(g, e)
+		g = resizeZero(g, k)
+	}
+
+	return g
+}
+
+// reverse returns the coefficients of p in reverse order
+func reverse(p Polynomial) Polynomial {
+	res := make(Polynomial, len(p))
+	for i := 0; i < len(p); i++ {
+		res[i] = p[len(p)-1-i]
+	}
+	return res
+}
+
+// truncate returns p mod X**n
+func truncate(p Polynomial, n int) Polynomial {
+	if len(p) > n {
+		return p[:n]
+	}
+	return p
+}
+
+// resizeZero returns p with exactly n coefficients, truncated or padded with zeros
+func resizeZero(p Polynomial, n int) Polynomial {
+	if len(p) >= n {
+		return p[:n]
+	}
+	res := make(Polynomial, n)
+	copy(res, p)
+	return res
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func randomPoints(n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func TestMultiEvaluate(t *testing.T) {
+
+	for _, sizes := range [][2]int{{1, 1}, {10, 3}, {3, 10}, {100, 100}, {300, 257}} {
+		p := randomPolynomial(sizes[0])
+		points := randomPoints(sizes[1])
+
+		evaluations := p.MultiEvaluate(points)
+		if len(evaluations) != len(points) {
+			t.Fatal("there should be one evaluation per point")
+		}
+		for i := 0; i < len(points); i++ {
+			if expected := p.Evaluate(points[i]); !evaluations[i].Equal(&expected) {
+				t.Fatalf("wrong evaluation of a polynomial of size %d at %d points", sizes[0], sizes[1])
+			}
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+
+	for _, n := range []int{1, 2, 17, 300} {
+		p := randomPolynomial(n)
+		xs := randomPoints(n)
+		ys := p.MultiEvaluate(xs)
+
+		q, err := Interpolate(xs, ys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(q) != n || !q.Equal(p) {
+			t.Fatalf("wrong interpolation of %d points", n)
+		}
+	}
+
+	xs, ys := randomPoints(10), randomPoints(10)
+	xs[7] = xs[2]
+	if _, err := Interpolate(xs, ys); err != ErrDuplicatePoint {
+		t.Fatal("interpolation with duplicate points should fail")
+	}
+	if _, err := Interpolate(xs, ys[1:]); err != ErrInvalidNbPoints {
+		t.Fatal("interpolation with a wrong number of values should fail")
+	}
+}
+
+func TestRem(t *testing.T) {
+
+	// the Newton iteration and the schoolbook division agree
+	for _, sizes := range [][2]int{{200, 64}, {129, 100}, {1000, 40}} {
+		a := randomPolynomial(sizes[0])
+		b := randomPolynomial(sizes[1])
+		b[len(b)-1].SetOne()
+
+		_, expected, err := a.Div(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !rem(a, b).Equal(expected) {
+			t.Fatalf("wrong remainder of the division of sizes %d and %d", sizes[0], sizes[1])
+		}
+	}
+}
+
+func BenchmarkMultiEvaluate(b *testing.B) {
+	const size = 1 << 10
+	p := randomPolynomial(size)
+	points := randomPoints(size)
+
+	b.Run("subproduct tree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p.MultiEvaluate(points)
+		}
+	})
+	b.Run("horner", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < len(points); j++ {
+				p.Evaluate(points[j])
+			}
+		}
+	})
+}
+
+func BenchmarkInterpolate(b *testing.B) {
+	const size = 1 << 10
+	xs, ys := randomPoints(size), randomPoints(size)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Interpolate(xs, ys)
+	}
+}
@@ -29,15 +29,71 @@ func (p Polynomial) Degree() uint64 {
 	return res
 }
 
-// Eval evaluates p at v
-func (p Polynomial) Eval(v interface{}) interface{} {
-	var res, _v fr.Element
-	_v.Set(v.(*fr.Element))
-	s := len(p)
-	res.Set(&p[s-1])
-	for i := s - 2; i >= 0; i-- {
-		res.Mul(&res, &_v)
+// Evaluate evaluates p at x with Horner's method
+func (p Polynomial) Evaluate(x fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(p) - 1; i >= 0; i-- {
+		res.Mul(&res, &x)
 		res.Add(&res, &p[i])
 	}
+	return res
+}
+
+// Eval evaluates p at v, it implements the generic polynomial.Polynomial interface.
+//
+// Eval panics if v is not a *fr.Element, Evaluate should be preferred.
+func (p Polynomial) Eval(v interface{}) interface{} {
+	res := p.Evaluate(*v.(*fr.Element))
 	return &res
 }
+
+// BatchInvert returns the inverses of the elements of a, which must be non zero,
+// using Montgomery's trick
+func BatchInvert(a []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	var acc fr.Element
+	acc.SetOne()
+	for i := 0; i < len(a); i++ {
+		res[i] = acc
+		acc.Mul(&acc, &a[i])
+	}
+	acc.Inverse(&acc)
+	for i := len(a) - 1; i >= 0; i-- {
+		res[i].Mul(&res[i], &acc)
+		acc.Mul(&acc, &a[i])
+	}
+
+	return res
+}
+
+// BatchInvertOrZero returns the inverses of the elements of a as BatchInvert does,
+// except that the zero elements are mapped to zero
+func BatchInvertOrZero(a []fr.Element) []fr.Element {
+	res := make([]fr.Element, len(a))
+	zeroes := make([]bool, len(a))
+
+	var acc fr.Element
+	acc.SetOne()
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i] = acc
+		acc.Mul(&acc, &a[i])
+	}
+	acc.Inverse(&acc)
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &acc)
+		acc.Mul(&acc, &a[i])
+	}
+
+	return res
+}
@@ -0,0 +1,255 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+func randomPolynomial(size int) Polynomial {
+	f := make(Polynomial, size)
+	for i := 0; i < size; i++ {
+		f[i].SetRandom()
+	}
+	return f
+}
+
+func TestEqual(t *testing.T) {
+
+	p := randomPolynomial(10)
+	q := append(p.Clone(), fr.Element{}, fr.Element{})
+	if !p.Equal(q) || !q.Equal(p) {
+		t.Fatal("trailing zeros should be ignored")
+	}
+	q[3].Double(&q[3])
+	if p.Equal(q) {
+		t.Fatal("polynomials with different coefficients should differ")
+	}
+	if !(Polynomial{fr.Element{}}).IsZero() || p.IsZero() {
+		t.Fatal("wrong zero polynomial")
+	}
+}
+
+func TestAddSubScale(t *testing.T) {
+
+	p1, p2 := randomPolynomial(20), randomPolynomial(13)
+	var x, c fr.Element
+	x.SetRandom()
+	c.SetRandom()
+	y1, y2 := p1.Evaluate(x), p2.Evaluate(x)
+
+	var sum, diff, scaled Polynomial
+	sum.Add(p1, p2)
+	diff.Sub(p2, p1)
+	scaled.ScaleBy(p1, &c)
+
+	var expected fr.Element
+	expected.Add(&y1, &y2)
+	if v := sum.Evaluate(x); len(sum) != 20 || !v.Equal(&expected) {
+		t.Fatal("wrong sum")
+	}
+	expected.Sub(&y2, &y1)
+	if v := diff.Evaluate(x); len(diff) != 20 || !v.Equal(&expected) {
+		t.Fatal("wrong difference")
+	}
+	expected.Mul(&y1, &c)
+	if v := scaled.Evaluate(x); !v.Equal(&expected) {
+		t.Fatal("wrong scaling")
+	}
+
+	// the result can alias the operands
+	p := p2.Clone()
+	p.Add(p1, p).Sub(p, p1)
+	if !p.Equal(p2) {
+		t.Fatal("p1 + p2 - p1 should be p2")
+	}
+}
+
+func TestMul(t *testing.T) {
+
+	for _, sizes := range [][2]int{{1, 1}, {5, 17}, {40, 33}, {100, 200}} {
+		p1, p2 := randomPolynomial(sizes[0]), randomPolynomial(sizes[1])
+
+		var p Polynomial
+		p.Mul(p1, p2)
+		if len(p) != sizes[0]+sizes[1]-1 {
+			t.Fatal("wrong size of the product")
+		}
+
+		var x fr.Element
+		x.SetRandom()
+		y1, y2 := p1.Evaluate(x), p2.Evaluate(x)
+		y1.Mul(&y1, &y2)
+		if y := p.Evaluate(x); !y.Equal(&y1) {
+			t.Fatalf("wrong product of polynomials of sizes %d and %d", sizes[0], sizes[1])
+		}
+
+		// both methods agree
+		if !mulSchoolbook(p1, p2).Equal(mulFFT(p1, p2)) {
+			t.Fatal("schoolbook and FFT multiplications differ")
+		}
+	}
+
+	var p Polynomial
+	if !p.Mul(randomPolynomial(3), nil).IsZero() {
+		t.Fatal("the product by the zero polynomial should be zero")
+	}
+}
+
+func TestDiv(t *testing.T) {
+
+	for _, sizes := range [][2]int{{20, 7}, {7, 7}, {5, 1}, {3, 10}} {
+		p, d := randomPolynomial(sizes[0]), randomPolynomial(sizes[1])
+		q, r, err := p.Div(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r) >= len(d) {
+			t.Fatal("the degree of the remainder should be smaller than the degree of the divisor")
+		}
+
+		// p = q*d + r
+		var res Polynomial
+		res.Mul(q, d).Add(res, r)
+		if !res.Equal(p) {
+			t.Fatal("wrong euclidean division")
+		}
+	}
+
+	// exact division
+	p1, p2 := randomPolynomial(12), randomPolynomial(5)
+	var p Polynomial
+	p.Mul(p1, p2)
+	q, r, err := p.Div(p2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Equal(p1) || !r.IsZero() {
+		t.Fatal("wrong exact division")
+	}
+
+	if _, _, err := p.Div(Polynomial{fr.Element{}}); err != ErrDivisionByZero {
+		t.Fatal("division by zero should fail")
+	}
+}
+
+func TestDivideByXMinusA(t *testing.T) {
+
+	p := randomPolynomial(30)
+	var a fr.Element
+	a.SetRandom()
+
+	q, r := p.DivideByXMinusA(a)
+	if y := p.Evaluate(a); !r.Equal(&y) {
+		t.Fatal("the remainder should be p(a)")
+	}
+
+	// p = q*(X - a) + r
+	var xMinusA fr.Element
+	xMinusA.Neg(&a)
+	var res Polynomial
+	res.Mul(q, Polynomial{xMinusA, fr.One()}).Add(res, Polynomial{r})
+	if !res.Equal(p) {
+		t.Fatal("wrong division by X - a")
+	}
+}
+
+func TestDerivative(t *testing.T) {
+
+	// (p1*p2)' = p1'*p2 + p1*p2'
+	p1, p2 := randomPolynomial(10), randomPolynomial(7)
+	var p, a, b Polynomial
+	p.Mul(p1, p2)
+	a.Mul(p1.Derivative(), p2)
+	b.Mul(p1, p2.Derivative())
+	a.Add(a, b)
+	if !p.Derivative().Equal(a) {
+		t.Fatal("wrong derivative")
+	}
+
+	if !(Polynomial{fr.One()}).Derivative().IsZero() {
+		t.Fatal("the derivative of a constant should be zero")
+	}
+}
+
+func TestCompose(t *testing.T) {
+
+	p, q := randomPolynomial(10), randomPolynomial(4)
+	r := p.Compose(q)
+	if len(r) != 9*3+1 {
+		t.Fatal("wrong degree of the composition")
+	}
+
+	var x fr.Element
+	x.SetRandom()
+	expected := p.Evaluate(q.Evaluate(x))
+	if y := r.Evaluate(x); !y.Equal(&expected) {
+		t.Fatal("wrong composition")
+	}
+}
+
+func BenchmarkMul(b *testing.B) {
+	const size = 1 << 12
+	p1, p2 := randomPolynomial(size), randomPolynomial(size)
+
+	b.Run("schoolbook", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			mulSchoolbook(p1, p2)
+		}
+	})
+	b.Run("fft", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			mulFFT(p1, p2)
+		}
+	})
+}
+
+func TestBatchInvert(t *testing.T) {
+
+	a := randomPolynomial(20)
+	a[3].SetZero()
+	a[11].SetZero()
+
+	checkInverses := func(inv []fr.Element) {
+		var one fr.Element
+		for i := 0; i < len(a); i++ {
+			if a[i].IsZero() {
+				continue
+			}
+			one.Mul(&a[i], &inv[i])
+			if one != fr.One() {
+				t.Fatal("wrong inverse")
+			}
+		}
+	}
+
+	// the zero elements are mapped to zero, the other ones are inverted
+	inv := BatchInvertOrZero(a)
+	checkInverses(inv)
+	if !inv[3].IsZero() || !inv[11].IsZero() {
+		t.Fatal("the inverse of zero should be zero")
+	}
+
+	a[3].SetOne()
+	a[11].SetOne()
+	checkInverses(BatchInvert(a))
+	if len(BatchInvert(nil)) != 0 {
+		t.Fatal("the inverses of no element should be empty")
+	}
+}
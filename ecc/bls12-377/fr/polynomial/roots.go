@@ -0,0 +1,364 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+var (
+	ErrZeroPolynomial = errors.New("the zero polynomial has no factorization")
+	ErrNotSplit       = errors.New("the polynomial is not a product of distinct linear factors")
+)
+
+// maxSplitAttempts bounds the number of random splittings tried on a factor by SplitRoots,
+// a product of k >= 2 distinct linear factors is not split with probability 2**(1-k) <= 1/2
+const maxSplitAttempts = 64
+
+// Factor irreducible factor of a polynomial
+type Factor struct {
+	Polynomial   Polynomial // monic and irreducible
+	Multiplicity int
+}
+
+// Monic returns p divided by its leading coefficient, and the zero polynomial if p is zero
+func (p Polynomial) Monic() Polynomial {
+	p = p.trim()
+	if len(p) == 0 {
+		return Polynomial{}
+	}
+	var lcInv fr.Element
+	lcInv.Inverse(&p[len(p)-1])
+	var res Polynomial
+	return *res.ScaleBy(p, &lcInv)
+}
+
+// GCD returns the monic greatest common divisor of p and q, computed with the euclidean algorithm
+func GCD(p, q Polynomial) Polynomial {
+	a, b := p.trim(), q.trim()
+	for len(b) != 0 {
+		_, r, _ := a.Div(b)
+		a, b = b, r
+	}
+	return a.Monic()
+}
+
+// Factorize returns the irreducible factors of p over fr, with their multiplicities.
+// The constant factor is dropped: p is the leading coefficient of p times the product of the
+// factors to the power of their multiplicities.
+//
+// p is made square free with Yun's algorithm, its square free parts are split by degree with
+// the distinct degree factorization, and each product of irreducible factors of the same degree
+// is split with the probabilistic algorithm of Cantor and Zassenhaus.
+func (p Polynomial) Factorize() ([]Factor, error) {
+	f := p.Monic()
+	if len(f) == 0 {
+		return nil, ErrZeroPolynomial
+	}
+
+	var res []Factor
+	for _, sf := range squareFreeFactorization(f) {
+		for _, dd := range distinctDegreeFactorization(sf.Polynomial) {
+			factors, err := equalDegreeFactorization(dd.Polynomial, dd.Multiplicity)
+			if err != nil {
+				return nil, err
+			}
+			for _, g := range factors {
+				res = append(res, Factor{Polynomial: g, Multiplicity: sf.Multiplicity})
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// Roots returns the distinct roots of p in fr, in no particular order.
+//
+// The product of the linear factors of p is gcd(p, X**q - X), q being the size of fr,
+// which is then split with the algorithm of Cantor and Zassenhaus.
+func (p Polynomial) Roots() ([]fr.Element, error) {
+	f := p.Monic()
+	if len(f) == 0 {
+		return nil, ErrZeroPolynomial
+	}
+	if len(f) == 1 {
+		return []fr.Element{}, nil
+	}
+
+	// X**q - X mod f
+	x := Polynomial{fr.Element{}, fr.One()}
+	var xq Polynomial
+	xq.Sub(powMod(x, fr.Modulus(), f), x)
+
+	linear := GCD(f, xq.trim())
+	if len(linear) == 1 {
+		return []fr.Element{}, nil
+	}
+	factors, err := equalDegreeFactorization(linear, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return linearRoots(factors), nil
+}
+
+// SplitRoots returns the roots of p, which must be a product of distinct linear factors,
+// as the characteristic polynomial Prod_i (X - x_i) of a set {x_i}. It skips the search
+// of the linear factors of Roots, ErrNotSplit is returned if p does not split.
+func (p Polynomial) SplitRoots() ([]fr.Element, error) {
+	f := p.Monic()
+	if len(f) == 0 {
+		return nil, ErrZeroPolynomial
+	}
+	if len(f) == 1 {
+		return []fr.Element{}, nil
+	}
+
+	factors, err := splitLinear(f, maxSplitAttempts)
+	if err != nil {
+		return nil, err
+	}
+	roots := linearRoots(factors)
+
+	// the roots must be distinct
+	seen := make(map[fr.Element]struct{}, len(roots))
+	for _, r := range roots {
+		if _, ok := seen[r]; ok {
+			return nil, ErrNotSplit
+		}
+		seen[r] = struct{}{}
+	}
+
+	return roots, nil
+}
+
+// squareFreeFactorization returns the square free polynomials a_i such that f = Prod_i a_i**i,
+// with Yun's algorithm. f is monic, and its degree is smaller than the characteristic of fr.
+func squareFreeFactorization(f Polynomial) []Factor {
+	var res []Factor
+
+	df := f.Derivative().trim()
+	b := GCD(f, df)
+	c, _, _ := f.Div(b)
+	d, _, _ := df.Div(b)
+	d.Sub(d, c.Derivative())
+	for i := 1; len(c) > 1; i++ {
+		a := GCD(c, d.trim())
+		if len(a) > 1 {
+			res = append(res, Factor{Polynomial: a, Multiplicity: i})
+		}
+		c, _, _ = c.Div(a)
+		d, _, _ = d.Div(a)
+		d.Sub(d, c.Derivative())
+	}
+
+	return res
+}
+
+// distinctDegreeFactorization returns the products of the irreducible factors of f of each degree,
+// the degree being stored in the Multiplicity of the returned Factors. f is monic and square free.
+func distinctDegreeFactorization(f Polynomial) []Factor {
+	var res []Factor
+
+	q := fr.Modulus()
+	x := Polynomial{fr.Element{}, fr.One()}
+	h := x
+	for d := 1; 2*d <= len(f)-1; d++ {
+
+		// h = X**(q**d) mod f, gcd(f, h - X) is the product of the factors whose degree divides d
+		h = powMod(h, q, f)
+		var t Polynomial
+		g := GCD(f, t.Sub(h, x).trim())
+		if len(g) > 1 {
+			res = append(res, Factor{Polynomial: g, Multiplicity: d})
+			f, _, _ = f.Div(g)
+			h = rem(h, f)
+		}
+	}
+	if len(f) > 1 {
+		res = append(res, Factor{Polynomial: f, Multiplicity: len(f) - 1})
+	}
+
+	return res
+}
+
+// equalDegreeFactorization returns the irreducible factors of f, which is monic, square free
+// and a product of irreducible factors of degree d
+func equalDegreeFactorization(f Polynomial, d int) ([]Polynomial, error) {
+	if d == 1 {
+		return splitLinear(f, -1)
+	}
+
+	// e = (q**d - 1)/2
+	e := new(big.Int).Exp(fr.Modulus(), big.NewInt(int64(d)), nil)
+	e.Sub(e, big.NewInt(1)).Rsh(e, 1)
+
+	todo := []Polynomial{f}
+	var res []Polynomial
+	for len(todo) > 0 {
+		g := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		if len(g)-1 == d {
+			res = append(res, g)
+			continue
+		}
+
+		// gcd(g, a**e - 1) is a non trivial factor of g with probability about 1/2
+		a, err := sampleRandom(len(g) - 1)
+		if err != nil {
+			return nil, err
+		}
+		var h Polynomial
+		h.Sub(powMod(a, e, g), Polynomial{fr.One()})
+		s := GCD(g, h.trim())
+		if len(s) == 1 || len(s) == len(g) {
+			todo = append(todo, g)
+			continue
+		}
+		t, _, _ := g.Div(s)
+		todo = append(todo, s, t)
+	}
+
+	return res, nil
+}
+
+// splitLinear returns the linear factors of f, which is monic and a product of distinct linear
+// factors, with the algorithm of Cantor and Zassenhaus: gcd(f, (X + a)**((q-1)/2) - 1) is the product
+// of the X - x_i for which x_i + a is a non zero square. The factors of degree 2 are split with
+// a square root. If maxAttempts >= 0, ErrNotSplit is returned when a factor has not been split after
+// maxAttempts random a.
+func splitLinear(f Polynomial, maxAttempts int) ([]Polynomial, error) {
+	e := fr.Modulus()
+	e.Sub(e, big.NewInt(1)).Rsh(e, 1)
+
+	todo := []Polynomial{f}
+	var res []Polynomial
+	for len(todo) > 0 {
+		g := todo[len(todo)-1]
+		todo = todo[:len(todo)-1]
+		switch len(g) - 1 {
+		case 1:
+			res = append(res, g)
+			continue
+		case 2:
+			x1, x2, ok := quadraticRoots(g)
+			if !ok {
+				return nil, ErrNotSplit
+			}
+			res = append(res, linear(x1), linear(x2))
+			continue
+		}
+
+		split := false
+		for attempt := 0; !split && (maxAttempts < 0 || attempt < maxAttempts); attempt++ {
+			var a fr.Element
+			if _, err := a.SetRandom(); err != nil {
+				return nil, err
+			}
+			var h Polynomial
+			h.Sub(powMod(Polynomial{a, fr.One()}, e, g), Polynomial{fr.One()})
+			s := GCD(g, h.trim())
+			if len(s) > 1 && len(s) < len(g) {
+				t, _, _ := g.Div(s)
+				todo = append(todo, s, t)
+				split = true
+			}
+		}
+		if !split {
+			return nil, ErrNotSplit
+		}
+	}
+
+	return res, nil
+}
+
+// quadraticRoots returns the roots of the monic polynomial X**2 + b*X + c,
+// or false if they are not in fr
+func quadraticRoots(g Polynomial) (fr.Element, fr.Element, bool) {
+
+	// x = (-b +- sqrt(b**2 - 4c))/2
+	var delta, t, s fr.Element
+	delta.Square(&g[1])
+	t.Double(&g[0]).Double(&t)
+	delta.Sub(&delta, &t)
+	if s.Sqrt(&delta) == nil {
+		return fr.Element{}, fr.Element{}, false
+	}
+
+	var twoInv, x1, x2 fr.Element
+	twoInv.SetUint64(2).Inverse(&twoInv)
+	x1.Sub(&s, &g[1]).Mul(&x1, &twoInv)
+	x2.Neg(&s).Sub(&x2, &g[1]).Mul(&x2, &twoInv)
+	return x1, x2, true
+}
+
+// linear returns X - x
+func linear(x fr.Element) Polynomial {
+	res := Polynomial{x, fr.One()}
+	res[0].Neg(&res[0])
+	return res
+}
+
+// linearRoots returns the roots of monic linear factors
+func linearRoots(factors []Polynomial) []fr.Element {
+	res := make([]fr.Element, len(factors))
+	for i := 0; i < len(factors); i++ {
+		res[i].Neg(&factors[i][0])
+	}
+	return res
+}
+
+// powMod returns a**e mod f, f being monic. The inverse of the reverse of f used by the
+// Newton division is computed once for all the reductions.
+func powMod(a Polynomial, e *big.Int, f Polynomial) Polynomial {
+	var fRevInv Polynomial
+	if len(f) >= mulFFTThreshold {
+		fRevInv = invModXn(reverse(f), len(f)-1)
+	}
+	reduce := func(p Polynomial) Polynomial {
+		if len(p)-len(f)+1 < mulFFTThreshold || fRevInv == nil {
+			return rem(p, f)
+		}
+		return remNewton(p, f, fRevInv)
+	}
+
+	a = rem(a, f)
+	res := Polynomial{fr.One()}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res.Mul(res, res)
+		res = reduce(res)
+		if e.Bit(i) == 1 {
+			res.Mul(res, a)
+			res = reduce(res)
+		}
+	}
+	return res
+}
+
+// sampleRandom returns a random polynomial with n coefficients
+func sampleRandom(n int) (Polynomial, error) {
+	res := make(Polynomial, n)
+	for i := 0; i < n; i++ {
+		if _, err := res[i].SetRandom(); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
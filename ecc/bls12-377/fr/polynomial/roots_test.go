@@ -0,0 +1,207 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package polynomial
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// characteristicPolynomial returns Prod_i (X - points[i])
+func characteristicPolynomial(points []fr.Element) Polynomial {
+	res := Polynomial{fr.One()}
+	for i := 0; i < len(points); i++ {
+		res.Mul(res, linear(points[i]))
+	}
+	return res
+}
+
+// sameSet returns true if a and b have the same elements, a being made of distinct elements
+func sameSet(a, b []fr.Element) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	elements := make(map[fr.Element]struct{}, len(a))
+	for _, x := range a {
+		elements[x] = struct{}{}
+	}
+	for _, x := range b {
+		if _, ok := elements[x]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// irreducible returns a random monic irreducible polynomial of degree 2 or 3,
+// which are irreducible if and only if they have no roots
+func irreducible(t *testing.T, degree int) Polynomial {
+	for {
+		p := randomPolynomial(degree + 1)
+		p[degree].SetOne()
+		roots, err := p.Roots()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(roots) == 0 {
+			return p
+		}
+	}
+}
+
+func TestGCD(t *testing.T) {
+
+	a, b, c := randomPolynomial(10), randomPolynomial(7), randomPolynomial(5)
+	var ac, bc Polynomial
+	ac.Mul(a, c)
+	bc.Mul(b, c)
+
+	// a and b are coprime with high probability
+	if g := GCD(ac, bc); !g.Equal(c.Monic()) {
+		t.Fatal("wrong gcd")
+	}
+	if g := GCD(ac, Polynomial{}); !g.Equal(ac.Monic()) {
+		t.Fatal("gcd(p, 0) should be p made monic")
+	}
+	points := randomPoints(2)
+	if g := GCD(linear(points[0]), linear(points[1])); !g.Equal(Polynomial{fr.One()}) {
+		t.Fatal("the gcd of coprime polynomials should be 1")
+	}
+}
+
+func TestSplitRoots(t *testing.T) {
+
+	for _, n := range []int{1, 2, 3, 10, 100} {
+		points := randomPoints(n)
+		p := characteristicPolynomial(points)
+		var c fr.Element
+		c.SetRandom()
+		p.ScaleBy(p, &c)
+
+		roots, err := p.SplitRoots()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameSet(points, roots) {
+			t.Fatalf("wrong roots of a product of %d linear factors", n)
+		}
+	}
+
+	// repeated root
+	points := randomPoints(5)
+	points[3] = points[1]
+	if _, err := characteristicPolynomial(points).SplitRoots(); err != ErrNotSplit {
+		t.Fatal("a polynomial with a double root should be rejected")
+	}
+
+	// irreducible factor
+	var p Polynomial
+	p.Mul(characteristicPolynomial(randomPoints(3)), irreducible(t, 2))
+	if _, err := p.SplitRoots(); err != ErrNotSplit {
+		t.Fatal("a polynomial with an irreducible factor of degree 2 should be rejected")
+	}
+	p.Mul(characteristicPolynomial(randomPoints(3)), irreducible(t, 3))
+	if _, err := p.SplitRoots(); err != ErrNotSplit {
+		t.Fatal("a polynomial with an irreducible factor of degree 3 should be rejected")
+	}
+
+	if _, err := (Polynomial{}).SplitRoots(); err != ErrZeroPolynomial {
+		t.Fatal("the zero polynomial should be rejected")
+	}
+}
+
+func TestRoots(t *testing.T) {
+
+	// (X - x0)**2 * (X - x1) * (X - x2) * q2 * q3
+	points := randomPoints(3)
+	p := characteristicPolynomial([]fr.Element{points[0], points[0], points[1], points[2]})
+	p.Mul(p, irreducible(t, 2))
+	p.Mul(p, irreducible(t, 3))
+
+	roots, err := p.Roots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameSet(points, roots) {
+		t.Fatal("wrong roots")
+	}
+
+	// polynomials without roots
+	for _, p := range []Polynomial{{fr.One()}, irreducible(t, 2)} {
+		roots, err := p.Roots()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(roots) != 0 {
+			t.Fatal("the polynomial should have no roots")
+		}
+	}
+}
+
+func TestFactorize(t *testing.T) {
+
+	// c * (X - x0)**2 * (X - x1) * q2 * q3**3 * q3'
+	points := randomPoints(2)
+	expected := []Factor{
+		{Polynomial: linear(points[0]), Multiplicity: 2},
+		{Polynomial: linear(points[1]), Multiplicity: 1},
+		{Polynomial: irreducible(t, 2), Multiplicity: 1},
+		{Polynomial: irreducible(t, 3), Multiplicity: 3},
+		{Polynomial: irreducible(t, 3), Multiplicity: 1},
+	}
+	var c fr.Element
+	c.SetRandom()
+	p := Polynomial{c}
+	for _, f := range expected {
+		for i := 0; i < f.Multiplicity; i++ {
+			p.Mul(p, f.Polynomial)
+		}
+	}
+
+	factors, err := p.Factorize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(factors) != len(expected) {
+		t.Fatalf("expected %d factors, got %d", len(expected), len(factors))
+	}
+	for _, e := range expected {
+		found := false
+		for _, f := range factors {
+			if f.Polynomial.Equal(e.Polynomial) && f.Multiplicity == e.Multiplicity {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatal("missing factor")
+		}
+	}
+
+	if _, err := (Polynomial{}).Factorize(); err != ErrZeroPolynomial {
+		t.Fatal("the zero polynomial should be rejected")
+	}
+}
+
+func BenchmarkSplitRoots(b *testing.B) {
+	p := characteristicPolynomial(randomPoints(256))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.SplitRoots()
+	}
+}
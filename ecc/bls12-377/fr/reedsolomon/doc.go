@@ -0,0 +1,23 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package reedsolomon implements Reed-Solomon codes over the scalar field of bls12-377.
+//
+// The data, k elements of fr, are the coefficients of a polynomial p of degree < k, and
+// its codeword is made of the n evaluations p(w**i), w being the generator of a fft.Domain
+// of size n. Any k shares (i, p(w**i)) recover the data, and a codeword with at most
+// (n - k)/2 wrong shares is corrected with the decoding algorithm of Gao.
+package reedsolomon
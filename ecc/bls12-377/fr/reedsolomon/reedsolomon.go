@@ -0,0 +1,205 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package reedsolomon
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+)
+
+var (
+	ErrInvalidParameters = errors.New("the length of the code must be a power of 2, greater than its dimension which must be positive")
+	ErrInvalidDataSize   = errors.New("the size of the data is not the dimension of the code")
+	ErrInvalidShares     = errors.New("the indexes of the shares must be distinct and smaller than the length of the code, with one value per index")
+	ErrTooFewShares      = errors.New("not enough shares to recover the data")
+	ErrTooManyErrors     = errors.New("too many errors to decode the shares")
+)
+
+// Codec Reed-Solomon code of dimension K and length N, the codeword of the data d being the evaluations
+// of the polynomial d_0 + d_1*X + ... + d_{K-1}*X**(K-1) on the Domain of size N, in natural order.
+type Codec struct {
+	K, N   int
+	Domain *fft.Domain
+
+	// points of the domain, points[i] = Domain.Generator**i
+	points []fr.Element
+}
+
+// NewCodec returns a Reed-Solomon codec extending data of size k to codewords of size n.
+// n must be a power of 2 greater than k.
+func NewCodec(k, n int) (*Codec, error) {
+	if k <= 0 || n < k || n&(n-1) != 0 {
+		return nil, ErrInvalidParameters
+	}
+
+	domain, err := fft.NewDomainE(uint64(n), 0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	res := Codec{
+		K:      k,
+		N:      n,
+		Domain: domain,
+		points: make([]fr.Element, n),
+	}
+	res.points[0].SetOne()
+	for i := 1; i < n; i++ {
+		res.points[i].Mul(&res.points[i-1], &res.Domain.Generator)
+	}
+
+	return &res, nil
+}
+
+// Encode returns the codeword of data, computed with a FFT
+func (c *Codec) Encode(data []fr.Element) ([]fr.Element, error) {
+	if len(data) != c.K {
+		return nil, ErrInvalidDataSize
+	}
+	p, err := polynomial.Polynomial(data).ToLagrange(c.Domain)
+	if err != nil {
+		return nil, err
+	}
+	return p.Evaluations, nil
+}
+
+// Recover returns the data from the shares values[j] = codeword[indexes[j]], with a fast interpolation.
+// At least K shares are needed, only the first K ones are used: they are not checked against the others.
+func (c *Codec) Recover(indexes []int, values []fr.Element) ([]fr.Element, error) {
+	if err := c.checkShares(indexes, values); err != nil {
+		return nil, err
+	}
+
+	xs := make([]fr.Element, c.K)
+	for j := 0; j < c.K; j++ {
+		xs[j] = c.points[indexes[j]]
+	}
+	p, err := polynomial.Interpolate(xs, values[:c.K])
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Decode returns the data from the shares values[j] = codeword[indexes[j]], among which at most
+// (len(indexes) - K)/2 are wrong. It returns ErrTooManyErrors if the shares are not close enough
+// to a codeword.
+//
+// It follows Gao, "A New Algorithm for Decoding Reed-Solomon Codes" (2003): with g0 the vanishing
+// polynomial of the points of the m shares and g1 their interpolation polynomial, the extended euclidean
+// algorithm on (g0, g1) is stopped at the first remainder g = u*g0 + v*g1 of degree < (m + K)/2.
+// v is then the error locator, and the data is g/v.
+func (c *Codec) Decode(indexes []int, values []fr.Element) ([]fr.Element, error) {
+	if err := c.checkShares(indexes, values); err != nil {
+		return nil, err
+	}
+	m := len(indexes)
+
+	var g0, g1 polynomial.Polynomial
+	if m == c.N {
+
+		// all the shares: g0 = X**N - 1 and g1 is computed with an inverse FFT
+		evaluations := make([]fr.Element, c.N)
+		for j := 0; j < m; j++ {
+			evaluations[indexes[j]] = values[j]
+		}
+		lp, err := polynomial.NewLagrangePolynomial(c.Domain, evaluations)
+		if err != nil {
+			return nil, err
+		}
+		g1 = lp.ToCanonical()
+		g0 = make(polynomial.Polynomial, c.N+1)
+		g0[0].SetOne().Neg(&g0[0])
+		g0[c.N].SetOne()
+	} else {
+		xs := make([]fr.Element, m)
+		for j := 0; j < m; j++ {
+			xs[j] = c.points[indexes[j]]
+		}
+		var err error
+		if g1, err = polynomial.Interpolate(xs, values); err != nil {
+			return nil, err
+		}
+		g0 = vanishing(xs)
+	}
+
+	// partial extended euclidean algorithm, r_i = u_i*g0 + v_i*g1
+	r0, r1 := g0, trim(g1)
+	v0, v1 := polynomial.Polynomial{}, polynomial.Polynomial{fr.One()}
+	for 2*(len(r1)-1) >= m+c.K {
+		q, r, err := r0.Div(r1)
+		if err != nil {
+			return nil, err
+		}
+		var t polynomial.Polynomial
+		t.Mul(q, v1)
+		t.Sub(v0, t)
+		r0, r1 = r1, r
+		v0, v1 = v1, t
+	}
+
+	data, r, err := r1.Div(v1)
+	if err != nil || len(r) != 0 || len(data) > c.K {
+		return nil, ErrTooManyErrors
+	}
+	res := make([]fr.Element, c.K)
+	copy(res, data)
+
+	return res, nil
+}
+
+// checkShares returns an error if there are less than K shares, or if their indexes are invalid
+func (c *Codec) checkShares(indexes []int, values []fr.Element) error {
+	if len(indexes) != len(values) {
+		return ErrInvalidShares
+	}
+	if len(indexes) < c.K {
+		return ErrTooFewShares
+	}
+	seen := make([]bool, c.N)
+	for _, i := range indexes {
+		if i < 0 || i >= c.N || seen[i] {
+			return ErrInvalidShares
+		}
+		seen[i] = true
+	}
+	return nil
+}
+
+// vanishing returns Prod_i (X - xs[i])
+func vanishing(xs []fr.Element) polynomial.Polynomial {
+	if len(xs) == 1 {
+		res := polynomial.Polynomial{xs[0], fr.One()}
+		res[0].Neg(&res[0])
+		return res
+	}
+	var res polynomial.Polynomial
+	return *res.Mul(vanishing(xs[:len(xs)/2]), vanishing(xs[len(xs)/2:]))
+}
+
+// trim returns p without its trailing zero coefficients
+func trim(p polynomial.Polynomial) polynomial.Polynomial {
+	n := len(p)
+	for n > 0 && p[n-1].IsZero() {
+		n--
+	}
+	return p[:n]
+}
@@ -0,0 +1,203 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package reedsolomon
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+)
+
+func randomData(k int) []fr.Element {
+	res := make([]fr.Element, k)
+	for i := 0; i < k; i++ {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func equal(a, b []fr.Element) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if !a[i].Equal(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// shares returns m shares of codeword at random distinct indexes
+func shares(codeword []fr.Element, m int) ([]int, []fr.Element) {
+	indexes := rand.Perm(len(codeword))[:m]
+	values := make([]fr.Element, m)
+	for j, i := range indexes {
+		values[j] = codeword[i]
+	}
+	return indexes, values
+}
+
+// corrupt changes nbErrors random values
+func corrupt(values []fr.Element, nbErrors int) {
+	for _, j := range rand.Perm(len(values))[:nbErrors] {
+		var e fr.Element
+		e.SetRandom()
+		values[j].Add(&values[j], &e)
+	}
+}
+
+func TestEncode(t *testing.T) {
+
+	c, err := NewCodec(5, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := randomData(5)
+	codeword, err := c.Encode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the codeword is made of the evaluations of the data at the points of the domain
+	for i := 0; i < c.N; i++ {
+		var x, e, xi fr.Element
+		x.Exp(c.Domain.Generator, big.NewInt(int64(i)))
+		xi.SetOne()
+		for j := 0; j < len(data); j++ {
+			var t fr.Element
+			t.Mul(&data[j], &xi)
+			e.Add(&e, &t)
+			xi.Mul(&xi, &x)
+		}
+		if !e.Equal(&codeword[i]) {
+			t.Fatal("wrong codeword")
+		}
+	}
+
+	if _, err := c.Encode(randomData(6)); err != ErrInvalidDataSize {
+		t.Fatal("data larger than the dimension should be rejected")
+	}
+	for _, kn := range [][2]int{{0, 4}, {5, 4}, {3, 6}} {
+		if _, err := NewCodec(kn[0], kn[1]); err != ErrInvalidParameters {
+			t.Fatal("invalid parameters should be rejected")
+		}
+	}
+	if _, err := NewCodec(4, 1<<62); err != fft.ErrDomainTooLarge {
+		t.Fatal("a code longer than the largest 2-adic subgroup of fr should be rejected")
+	}
+}
+
+func TestRecover(t *testing.T) {
+
+	for _, kn := range [][2]int{{1, 2}, {4, 8}, {30, 64}, {64, 64}} {
+		c, err := NewCodec(kn[0], kn[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		data := randomData(c.K)
+		codeword, err := c.Encode(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		indexes, values := shares(codeword, c.K)
+		recovered, err := c.Recover(indexes, values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equal(data, recovered) {
+			t.Fatalf("failed to recover the data from %d shares out of %d", c.K, c.N)
+		}
+	}
+
+	c, _ := NewCodec(4, 8)
+	codeword, _ := c.Encode(randomData(4))
+	if _, err := c.Recover([]int{0, 1, 2}, codeword[:3]); err != ErrTooFewShares {
+		t.Fatal("recovering the data from too few shares should have failed")
+	}
+	if _, err := c.Recover([]int{0, 1, 2, 1}, codeword[:4]); err != ErrInvalidShares {
+		t.Fatal("duplicated shares should be rejected")
+	}
+	if _, err := c.Recover([]int{0, 1, 2, 8}, codeword[:4]); err != ErrInvalidShares {
+		t.Fatal("shares out of the codeword should be rejected")
+	}
+}
+
+func TestDecode(t *testing.T) {
+
+	const k, n = 20, 64
+	c, err := NewCodec(k, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := randomData(k)
+	codeword, err := c.Encode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// all the shares, or some of them, with as many errors as can be corrected
+	for _, m := range []int{n, 51, k} {
+		for _, nbErrors := range []int{0, (m - k) / 4, (m - k) / 2} {
+			indexes, values := shares(codeword, m)
+			corrupt(values, nbErrors)
+			decoded, err := c.Decode(indexes, values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equal(data, decoded) {
+				t.Fatalf("failed to decode %d shares with %d errors", m, nbErrors)
+			}
+		}
+	}
+
+	// too many errors
+	for _, m := range []int{n, 51} {
+		indexes, values := shares(codeword, m)
+		corrupt(values, (m-k)/2+1)
+		if decoded, err := c.Decode(indexes, values); err != ErrTooManyErrors && equal(data, decoded) {
+			t.Fatal("decoding shares with too many errors should have failed")
+		}
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	const k, n = 1 << 11, 1 << 12
+	c, _ := NewCodec(k, n)
+	data := randomData(k)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Encode(data)
+	}
+}
+
+func BenchmarkRecover(b *testing.B) {
+	const k, n = 1 << 11, 1 << 12
+	c, _ := NewCodec(k, n)
+	codeword, _ := c.Encode(randomData(k))
+	indexes, values := shares(codeword, k)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Recover(indexes, values)
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package sumcheck implements the sumcheck protocol of Lund, Fortnow, Karloff and Nisan
+// over the scalar field of bls12-377, made non interactive with Fiat Shamir.
+//
+// The prover convinces the verifier that Sum_{b in {0,1}**n} Prod_j m_j(b) = c, the m_j being
+// multilinear polynomials in n variables, or more generally that Sum_b f(m_1(b), m_2(b), ...) = c
+// for f a polynomial of low degree. After n rounds, the claim is reduced to the
+// evaluation of Prod_j m_j (or f(m_1, m_2, ...)) at a random point r, which the verifier checks by other means
+// (an opening of a commitment, or the next layer of a GKR proof).
+package sumcheck
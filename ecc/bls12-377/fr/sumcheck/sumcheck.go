@@ -0,0 +1,269 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package sumcheck
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidFactors = errors.New("the factors must be multilinear polynomials with the same positive number of variables")
+	ErrInvalidProof   = errors.New("can't verify sumcheck proof")
+)
+
+// Proof of a sumcheck on n variables
+type Proof struct {
+
+	// RoundPolynomials the univariate polynomials g_k sent by the prover at each round,
+	// g_k(X) = Sum_{b in {0,1}**(n-k-1)} f(m_1, m_2, ...)(r_1, ..., r_k, X, b), of degree the degree
+	// of f, represented by their evaluations at 0, 1, ..., degree.
+	RoundPolynomials [][]fr.Element
+}
+
+// Function of several variables, whose sum on the boolean hypercube is proven when it is
+// applied to multilinear polynomials
+type Function interface {
+
+	// Evaluate returns f(inputs)
+	Evaluate(inputs ...fr.Element) fr.Element
+
+	// Degree returns the total degree of f, which bounds the degree of the round polynomials
+	Degree() int
+}
+
+// product of its inputs
+type product int
+
+func (p product) Evaluate(inputs ...fr.Element) fr.Element {
+	res := inputs[0]
+	for j := 1; j < len(inputs); j++ {
+		res.Mul(&res, &inputs[j])
+	}
+	return res
+}
+
+func (p product) Degree() int {
+	return int(p)
+}
+
+// ChallengeNames returns the names of the challenges of a sumcheck on nbVars variables,
+// prefixed with prefix. They must be declared in the fiatshamir.Transcript given to Prove
+// and Verify, in this order.
+func ChallengeNames(prefix string, nbVars int) []string {
+	res := make([]string, nbVars)
+	for i := 0; i < nbVars; i++ {
+		res[i] = prefix + "sumcheck.r." + strconv.Itoa(i)
+	}
+	return res
+}
+
+// Prove returns a proof that Sum_{b in {0,1}**n} Prod_j factors[j](b) is the claimed sum,
+// along with the challenges r of the rounds and the evaluations factors[j](r) which
+// the verifier needs to check the final claim.
+//
+// The factors are folded in place, the challenges are derived from transcript with the
+// names ChallengeNames(prefix, n).
+func Prove(transcript *fiatshamir.Transcript, prefix string, factors []polynomial.MultiLin) (Proof, []fr.Element, []fr.Element, error) {
+	return ProveFunction(transcript, prefix, product(len(factors)), factors)
+}
+
+// ProveFunction returns a proof that Sum_{b in {0,1}**n} f(m_1(b), m_2(b), ...) is the claimed sum,
+// along with the challenges r of the rounds and the evaluations m_j(r). The proof is checked
+// by Verify with the degree f.Degree(), the final claim being f(m_1(r), m_2(r), ...).
+//
+// The multilinear polynomials are folded in place, the challenges are derived from transcript with
+// the names ChallengeNames(prefix, n).
+func ProveFunction(transcript *fiatshamir.Transcript, prefix string, f Function, m []polynomial.MultiLin) (Proof, []fr.Element, []fr.Element, error) {
+	nbVars, err := checkFactors(m)
+	if err != nil {
+		return Proof{}, nil, nil, err
+	}
+	names := ChallengeNames(prefix, nbVars)
+
+	proof := Proof{RoundPolynomials: make([][]fr.Element, nbVars)}
+	challenges := make([]fr.Element, nbVars)
+	for k := 0; k < nbVars; k++ {
+		proof.RoundPolynomials[k] = roundPolynomial(f, m)
+		challenges[k], err = deriveChallenge(transcript, names[k], proof.RoundPolynomials[k])
+		if err != nil {
+			return Proof{}, nil, nil, err
+		}
+		parallel.Execute(len(m), func(start, end int) {
+			for j := start; j < end; j++ {
+				m[j].Fold(challenges[k])
+			}
+		})
+	}
+
+	evaluations := make([]fr.Element, len(m))
+	for j := 0; j < len(m); j++ {
+		evaluations[j] = m[j][0]
+	}
+
+	return proof, challenges, evaluations, nil
+}
+
+// Verify checks the rounds of a proof that the sum on {0,1}**nbVars of a product of degree
+// multilinear polynomials (or of a Function of the given degree applied to multilinear
+// polynomials) is claimedSum.
+//
+// It returns the challenges r of the rounds and the final claim, the value of the product of
+// the polynomials (or of the Function) at r, which must be checked by the caller.
+func Verify(transcript *fiatshamir.Transcript, prefix string, claimedSum fr.Element, nbVars, degree int, proof *Proof) ([]fr.Element, fr.Element, error) {
+	if nbVars <= 0 || len(proof.RoundPolynomials) != nbVars {
+		return nil, fr.Element{}, ErrInvalidProof
+	}
+	names := ChallengeNames(prefix, nbVars)
+
+	claim := claimedSum
+	challenges := make([]fr.Element, nbVars)
+	var err error
+	for k := 0; k < nbVars; k++ {
+		g := proof.RoundPolynomials[k]
+		if len(g) != degree+1 {
+			return nil, fr.Element{}, ErrInvalidProof
+		}
+
+		// g_k(0) + g_k(1) = g_{k-1}(r_{k-1})
+		var sum fr.Element
+		sum.Add(&g[0], &g[1])
+		if !sum.Equal(&claim) {
+			return nil, fr.Element{}, ErrInvalidProof
+		}
+
+		challenges[k], err = deriveChallenge(transcript, names[k], g)
+		if err != nil {
+			return nil, fr.Element{}, err
+		}
+		claim = interpolate(g, challenges[k])
+	}
+
+	return challenges, claim, nil
+}
+
+// checkFactors returns the number of variables of the multilinear polynomials
+func checkFactors(factors []polynomial.MultiLin) (int, error) {
+	if len(factors) == 0 {
+		return 0, ErrInvalidFactors
+	}
+	n := len(factors[0])
+	if n < 2 || n&(n-1) != 0 {
+		return 0, ErrInvalidFactors
+	}
+	for j := 1; j < len(factors); j++ {
+		if len(factors[j]) != n {
+			return 0, ErrInvalidFactors
+		}
+	}
+	return factors[0].NbVars(), nil
+}
+
+// roundPolynomial returns the evaluations at 0, 1, ..., f.Degree() of
+// g(X) = Sum_b f(m_1(X, b), m_2(X, b), ...)
+func roundPolynomial(f Function, m []polynomial.MultiLin) []fr.Element {
+	degree := f.Degree()
+	mid := len(m[0]) / 2
+
+	res := make([]fr.Element, degree+1)
+	var lock sync.Mutex
+	parallel.Execute(mid, func(start, end int) {
+		partial := make([]fr.Element, degree+1)
+		values := make([]fr.Element, len(m))
+		steps := make([]fr.Element, len(m))
+		var eval fr.Element
+		for i := start; i < end; i++ {
+
+			// m_j(t, b) = m_j(0, b) + t*(m_j(1, b) - m_j(0, b))
+			for j := 0; j < len(m); j++ {
+				values[j] = m[j][i]
+				steps[j].Sub(&m[j][i+mid], &m[j][i])
+			}
+			for t := 0; t <= degree; t++ {
+				if t > 0 {
+					for j := 0; j < len(m); j++ {
+						values[j].Add(&values[j], &steps[j])
+					}
+				}
+				eval = f.Evaluate(values...)
+				partial[t].Add(&partial[t], &eval)
+			}
+		}
+
+		lock.Lock()
+		for t := 0; t <= degree; t++ {
+			res[t].Add(&res[t], &partial[t])
+		}
+		lock.Unlock()
+	})
+
+	return res
+}
+
+// interpolate returns g(r), g being the polynomial of degree len(evaluations)-1 whose
+// evaluations at 0, 1, ... are evaluations
+func interpolate(evaluations []fr.Element, r fr.Element) fr.Element {
+	var res, num, den, t fr.Element
+	for i := 0; i < len(evaluations); i++ {
+
+		// L_i(r) = Prod_{j != i} (r - j)/(i - j)
+		num.SetOne()
+		den.SetOne()
+		for j := 0; j < len(evaluations); j++ {
+			if j == i {
+				continue
+			}
+			t.SetUint64(uint64(j))
+			t.Sub(&r, &t)
+			num.Mul(&num, &t)
+			if i > j {
+				t.SetUint64(uint64(i - j))
+			} else {
+				t.SetUint64(uint64(j - i))
+				t.Neg(&t)
+			}
+			den.Mul(&den, &t)
+		}
+		den.Inverse(&den)
+		num.Mul(&num, &den).Mul(&num, &evaluations[i])
+		res.Add(&res, &num)
+	}
+	return res
+}
+
+// deriveChallenge derives the challenge name, binded to the round polynomial g
+func deriveChallenge(transcript *fiatshamir.Transcript, name string, g []fr.Element) (fr.Element, error) {
+	for i := 0; i < len(g); i++ {
+		b := g[i].Bytes()
+		if err := transcript.Bind(name, b[:]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	b, err := transcript.ComputeChallenge(name)
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var res fr.Element
+	res.SetBytes(b)
+	return res, nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package sumcheck
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+)
+
+const testPrefix = "test."
+
+func randomMultiLin(nbVars int) polynomial.MultiLin {
+	m := make(polynomial.MultiLin, 1<<nbVars)
+	for i := 0; i < len(m); i++ {
+		m[i].SetRandom()
+	}
+	return m
+}
+
+// sumOfProduct returns Sum_{b in {0,1}**n} Prod_j factors[j](b)
+func sumOfProduct(factors []polynomial.MultiLin) fr.Element {
+	var res, prod fr.Element
+	for i := 0; i < len(factors[0]); i++ {
+		prod.SetOne()
+		for j := 0; j < len(factors); j++ {
+			prod.Mul(&prod, &factors[j][i])
+		}
+		res.Add(&res, &prod)
+	}
+	return res
+}
+
+func cloneAll(factors []polynomial.MultiLin) []polynomial.MultiLin {
+	res := make([]polynomial.MultiLin, len(factors))
+	for j := 0; j < len(factors); j++ {
+		res[j] = factors[j].Clone()
+	}
+	return res
+}
+
+func TestSumcheck(t *testing.T) {
+
+	for _, nbVars := range []int{1, 2, 5} {
+		for _, degree := range []int{1, 2, 3} {
+
+			factors := make([]polynomial.MultiLin, degree)
+			for j := 0; j < degree; j++ {
+				factors[j] = randomMultiLin(nbVars)
+			}
+			sum := sumOfProduct(factors)
+			_factors := cloneAll(factors)
+
+			fs := fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+			proof, rProver, evaluations, err := Prove(&fs, testPrefix, _factors)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the evaluations are the ones of the factors at r
+			for j := 0; j < degree; j++ {
+				e, err := factors[j].Evaluate(rProver)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !e.Equal(&evaluations[j]) {
+					t.Fatal("wrong evaluation of a factor at the challenges")
+				}
+			}
+
+			fs = fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+			rVerifier, claim, err := Verify(&fs, testPrefix, sum, nbVars, degree, &proof)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k := 0; k < nbVars; k++ {
+				if !rVerifier[k].Equal(&rProver[k]) {
+					t.Fatal("the prover and the verifier should derive the same challenges")
+				}
+			}
+
+			// final claim
+			var prod fr.Element
+			prod.SetOne()
+			for j := 0; j < degree; j++ {
+				prod.Mul(&prod, &evaluations[j])
+			}
+			if !prod.Equal(&claim) {
+				t.Fatal("the final claim should be the product of the evaluations of the factors")
+			}
+		}
+	}
+}
+
+// testFunction f(a, b, c) = a*b*c + 2*a**2 + c
+type testFunction struct{}
+
+func (testFunction) Evaluate(inputs ...fr.Element) fr.Element {
+	var res, t fr.Element
+	res.Mul(&inputs[0], &inputs[1]).Mul(&res, &inputs[2])
+	t.Square(&inputs[0]).Double(&t)
+	res.Add(&res, &t).Add(&res, &inputs[2])
+	return res
+}
+
+func (testFunction) Degree() int {
+	return 3
+}
+
+func TestSumcheckFunction(t *testing.T) {
+
+	const nbVars = 4
+	var f testFunction
+
+	m := []polynomial.MultiLin{randomMultiLin(nbVars), randomMultiLin(nbVars), randomMultiLin(nbVars)}
+	var sum fr.Element
+	for i := 0; i < len(m[0]); i++ {
+		e := f.Evaluate(m[0][i], m[1][i], m[2][i])
+		sum.Add(&sum, &e)
+	}
+
+	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+	proof, _, evaluations, err := ProveFunction(&fs, testPrefix, f, cloneAll(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs = fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+	r, claim, err := Verify(&fs, testPrefix, sum, nbVars, f.Degree(), &proof)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j := 0; j < len(m); j++ {
+		e, err := m[j].Evaluate(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !e.Equal(&evaluations[j]) {
+			t.Fatal("wrong evaluation at the challenges")
+		}
+	}
+	if final := f.Evaluate(evaluations...); !final.Equal(&claim) {
+		t.Fatal("the final claim should be f applied to the evaluations")
+	}
+}
+
+func TestSumcheckInvalidProof(t *testing.T) {
+
+	const nbVars, degree = 4, 2
+
+	factors := []polynomial.MultiLin{randomMultiLin(nbVars), randomMultiLin(nbVars)}
+	sum := sumOfProduct(factors)
+	fs := fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+	proof, _, _, err := Prove(&fs, testPrefix, factors)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// wrong sum
+	var wrongSum fr.Element
+	wrongSum.Double(&sum)
+	fs = fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+	if _, _, err := Verify(&fs, testPrefix, wrongSum, nbVars, degree, &proof); err != ErrInvalidProof {
+		t.Fatal("verifying a proof against a wrong sum should have failed")
+	}
+
+	// tampered round
+	proof.RoundPolynomials[2][1].Double(&proof.RoundPolynomials[2][1])
+	fs = fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+	if _, _, err := Verify(&fs, testPrefix, sum, nbVars, degree, &proof); err != ErrInvalidProof {
+		t.Fatal("verifying a tampered proof should have failed")
+	}
+
+	// wrong number of rounds
+	proof.RoundPolynomials = proof.RoundPolynomials[:nbVars-1]
+	fs = fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+	if _, _, err := Verify(&fs, testPrefix, sum, nbVars, degree, &proof); err != ErrInvalidProof {
+		t.Fatal("verifying a proof with missing rounds should have failed")
+	}
+
+	// invalid factors
+	fs = fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+	if _, _, _, err := Prove(&fs, testPrefix, []polynomial.MultiLin{randomMultiLin(2), randomMultiLin(3)}); err != ErrInvalidFactors {
+		t.Fatal("factors with different number of variables should be rejected")
+	}
+}
+
+func BenchmarkProve(b *testing.B) {
+	const nbVars = 16
+	factors := []polynomial.MultiLin{randomMultiLin(nbVars), randomMultiLin(nbVars), randomMultiLin(nbVars)}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		_factors := cloneAll(factors)
+		fs := fiatshamir.NewTranscript(fiatshamir.SHA256, ChallengeNames(testPrefix, nbVars)...)
+		b.StartTimer()
+		Prove(&fs, testPrefix, _factors)
+	}
+}
@@ -69,6 +69,14 @@ func TestMapToCurveG1(t *testing.T) {
 		genFuzz1,
 	))
 
+	properties.Property("[G1] Svsw mapping should output point in the subgroup", prop.ForAll(
+		func(a fp.Element) bool {
+			g := MapToCurveG1Svdw(a)
+			return g.IsInSubGroup()
+		},
+		genFuzz1,
+	))
+
 	properties.Property("[G1] Svsw mapping should be deterministic", prop.ForAll(
 		func(a fp.Element) bool {
 			g1 := MapToCurveG1Svdw(a)
@@ -77,6 +85,8 @@ func TestMapToCurveG1(t *testing.T) {
 		},
 		genFuzz1,
 	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
 func TestG1AffineIsOnCurve(t *testing.T) {
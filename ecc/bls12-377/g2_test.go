@@ -62,21 +62,31 @@ func TestMapToCurveG2(t *testing.T) {
 	genFuzz1 := GenE2()
 
 	properties.Property("[G2] Svsw mapping should output point on the curve", prop.ForAll(
-		func(a fptower.E2) bool {
-			g := MapToCurveG2Svdw(a)
+		func(a *fptower.E2) bool {
+			g := MapToCurveG2Svdw(*a)
 			return g.IsOnCurve()
 		},
 		genFuzz1,
 	))
 
+	properties.Property("[G2] Svsw mapping should output point in the subgroup", prop.ForAll(
+		func(a *fptower.E2) bool {
+			g := MapToCurveG2Svdw(*a)
+			return g.IsInSubGroup()
+		},
+		genFuzz1,
+	))
+
 	properties.Property("[G2] Svsw mapping should be deterministic", prop.ForAll(
-		func(a fptower.E2) bool {
-			g1 := MapToCurveG2Svdw(a)
-			g2 := MapToCurveG2Svdw(a)
+		func(a *fptower.E2) bool {
+			g1 := MapToCurveG2Svdw(*a)
+			g2 := MapToCurveG2Svdw(*a)
 			return g1.Equal(&g2)
 		},
 		genFuzz1,
 	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
 func TestG2AffineIsOnCurve(t *testing.T) {
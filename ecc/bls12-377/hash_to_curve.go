@@ -123,6 +123,7 @@ func svdwMapG1(u fp.Element) G1Affine {
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#section-2.2.1
 func MapToCurveG1Svdw(t fp.Element) G1Affine {
 	res := svdwMapG1(t)
+	res.ClearCofactor(&res)
 	return res
 }
 
@@ -159,6 +160,24 @@ func HashToCurveG1Svdw(msg, dst []byte) (G1Affine, error) {
 // ----------------------------------------------------------------------------------------
 // G2Affine
 
+// constants of svdwMapG2, g(x) = x**3 + b being the equation of the twist
+// sage script to find z: https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#appendix-E.1
+// c1 = g(Z), c2 = -Z/2, c3 = sqrt(-g(Z)*3*Z**2) with sign0(c3), c4 = -4*g(Z)/(3*Z**2)
+var svdwZG2, svdwC1G2, svdwC2G2, svdwC3G2, svdwC4G2 fptower.E2
+
+func init() {
+	svdwZG2.A0.SetString("2")
+	svdwZG2.A1.SetString("1")
+	svdwC1G2.A0.SetString("258664426012969094010652733694893533536393512754914660539884262666720468348340822774968888139573360124440321458155")
+	svdwC1G2.A1.SetString("155198655607781456406391640216936120121836107652948796323930557600032281009004493664981332883744016074664192874913")
+	svdwC2G2.A0.SetString("258664426012969094010652733694893533536393512754914660539884262666720468348340822774968888139573360124440321458176")
+	svdwC2G2.A1.SetString("129332213006484547005326366847446766768196756377457330269942131333360234174170411387484444069786680062220160729088")
+	svdwC3G2.A0.SetString("98012010534863782184890770091865605164714227825330833372898276972600566885515997008918712948007603632564320052001")
+	svdwC3G2.A1.SetString("87367100364974488621549090379474790916473166621001142192302711175394885993635515493975993422958010326769737480998")
+	svdwC4G2.A0.SetString("8515701267916677992120254607239293285148757621560976478679317289439356982661426264196506605418053008212027043888")
+	svdwC4G2.A1.SetString("68551395206729257836568049588276310945447498853565860653368504179986823710424481426781878173615326716106817703318")
+}
+
 // https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#section-4.1
 // Shallue and van de Woestijne method, works for any elliptic curve in Weierstrass curve
 func svdwMapG2(u fptower.E2) G2Affine {
@@ -166,18 +185,7 @@ func svdwMapG2(u fptower.E2) G2Affine {
 	var res G2Affine
 
 	// constants
-	// sage script to find z: https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#appendix-E.1
-	var z, c1, c2, c3, c4 fptower.E2
-	z.A0.SetString("2")
-	z.A1.SetString("1")
-	c1.A0.SetString("38")
-	c1.A1.SetString("103465770405187637604261093477957413414557405101965864215953705066688187339336329109987555255829344049776128583288")
-	c2.A0.SetString("258664426012969094010652733694893533536393512754914660539884262666720468348340822774968888139573360124440321458176")
-	c2.A1.SetString("129332213006484547005326366847446766768196756377457330269942131333360234174170411387484444069786680062220160729088")
-	c3.A0.SetString("141354814213091513024678201993701334402983894315682828752154607295963278662565530559432544731234911957814229457027")
-	c3.A1.SetString("105105533309732059517333177844331590042891296673681156116453121436104838422613314245519046656808355505493854902221")
-	c4.A0.SetString("86221475337656364670217577898297844512131170918304886846628087555573489449446940924989629379857786708146773819395")
-	c4.A1.SetString("137954360540250183472348124637276551219409873469287818954604940088917583119115105479983407007772458733034838111024")
+	z, c1, c2, c3, c4 := svdwZG2, svdwC1G2, svdwC2G2, svdwC3G2, svdwC4G2
 
 	var tv1, tv2, tv3, tv4, one, x1, gx1, x2, gx2, x3, x, gx, y fptower.E2
 	one.SetOne()
@@ -0,0 +1,78 @@
+// Copyright 2020 ConsenSys AG
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bls12377
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/internal/fptower"
+)
+
+func TestMapToCurveG1SvdwClearsCofactor(t *testing.T) {
+
+	// the cofactor of G1 is not 1: svdwMapG1 maps to points of the curve outside of
+	// the subgroup, which MapToCurveG1Svdw must send to the subgroup
+	var u fp.Element
+	found := false
+	for i := uint64(1); i < 32; i++ {
+		u.SetUint64(i)
+		if p := svdwMapG1(u); p.IsOnCurve() && !p.IsInSubGroup() {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatal("svdwMapG1 should map some elements outside of the subgroup")
+	}
+	if p := MapToCurveG1Svdw(u); !p.IsInSubGroup() {
+		t.Fatal("MapToCurveG1Svdw should output a point in the subgroup")
+	}
+}
+
+func TestSvdwConstantsG2(t *testing.T) {
+
+	// g(Z) = Z**3 + b
+	var gz, zSquare3, tmp fptower.E2
+	gz.Square(&svdwZG2).Mul(&gz, &svdwZG2).Add(&gz, &bTwistCurveCoeff)
+	zSquare3.Square(&svdwZG2)
+	tmp.Double(&zSquare3)
+	zSquare3.Add(&zSquare3, &tmp)
+
+	if !svdwC1G2.Equal(&gz) {
+		t.Fatal("c1 should be g(Z)")
+	}
+
+	// 2*c2 = -Z
+	tmp.Double(&svdwC2G2).Add(&tmp, &svdwZG2)
+	if !tmp.IsZero() {
+		t.Fatal("c2 should be -Z/2")
+	}
+
+	// c3**2 = -g(Z)*3*Z**2, and sign0(c3)
+	var expected fptower.E2
+	expected.Mul(&gz, &zSquare3).Neg(&expected)
+	tmp.Square(&svdwC3G2)
+	if !tmp.Equal(&expected) || !sign0(svdwC3G2.A0) {
+		t.Fatal("c3 should be the square root of -g(Z)*3*Z**2 with sign0(c3)")
+	}
+
+	// c4*3*Z**2 = -4*g(Z)
+	tmp.Mul(&svdwC4G2, &zSquare3)
+	expected.Double(&gz).Double(&expected).Neg(&expected)
+	if !tmp.Equal(&expected) {
+		t.Fatal("c4 should be -4*g(Z)/(3*Z**2)")
+	}
+}
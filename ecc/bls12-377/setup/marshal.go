@@ -0,0 +1,76 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package setup
+
+import (
+	"io"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+)
+
+// WriteTo writes binary encoding of the transcript
+func (t *Transcript) WriteTo(w io.Writer) (int64, error) {
+	enc := curve.NewEncoder(w)
+
+	toEncode := []interface{}{
+		t.Powers.G1,
+		t.Powers.G2,
+		uint64(len(t.Contributions)),
+	}
+	for i := 0; i < len(t.Contributions); i++ {
+		c := &t.Contributions[i]
+		toEncode = append(toEncode, &c.TauG1, &c.Proof.G1S, &c.Proof.G1SX, &c.Proof.G2SR)
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes transcript data from reader.
+func (t *Transcript) ReadFrom(r io.Reader) (int64, error) {
+	dec := curve.NewDecoder(r)
+
+	var nbContributions uint64
+	toDecode := []interface{}{
+		&t.Powers.G1,
+		&t.Powers.G2,
+		&nbContributions,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	t.Contributions = make([]Contribution, nbContributions)
+	for i := 0; i < len(t.Contributions); i++ {
+		c := &t.Contributions[i]
+		for _, v := range []interface{}{&c.TauG1, &c.Proof.G1S, &c.Proof.G1SX, &c.Proof.G2SR} {
+			if err := dec.Decode(v); err != nil {
+				return dec.BytesRead(), err
+			}
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package setup
+
+import (
+	"errors"
+	"math/big"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+var (
+	ErrInvalidGenerator   = errors.New("the first power of tau is not the generator of the group")
+	ErrNotEnoughPowers    = errors.New("at least two powers of tau are needed in G1 and G2")
+	ErrInconsistentPowers = errors.New("the powers of tau are not consistent")
+	ErrInvalidPoint       = errors.New("invalid point: not on the curve or not in the correct subgroup")
+)
+
+// PowersOfTau powers of a secret tau, as produced by a powers of tau ceremony
+type PowersOfTau struct {
+	G1 []curve.G1Affine // [gen, [tau]gen, [tau**2]gen, ...]
+	G2 []curve.G2Affine // [gen, [tau]gen, [tau**2]gen, ...]
+}
+
+// Verify checks that the powers are consistent, that is that they start with the
+// generators of G1 and G2 and are successive powers of the same secret tau.
+//
+// Instead of checking each power separately, random linear combinations of the powers
+// are used, so that a single pairing check is needed: with r_i and rho random scalars,
+// e(Sum_i r_i*[tau**i]G1, [tau]G2) == e(Sum_i r_i*[tau**(i+1)]G1, G2)
+// e(rho*[tau]G1, Sum_i r_i*[tau**i]G2) == e(rho*G1, Sum_i r_i*[tau**(i+1)]G2)
+func (p *PowersOfTau) Verify() error {
+	if len(p.G1) < 2 || len(p.G2) < 2 {
+		return ErrNotEnoughPowers
+	}
+
+	_, _, g1, g2 := curve.Generators()
+	if !p.G1[0].Equal(&g1) || !p.G2[0].Equal(&g2) {
+		return ErrInvalidGenerator
+	}
+
+	return checkPowers(p.G1, p.G2, &p.G1[1], &p.G2[1], &g1, &g2)
+}
+
+// checkPowers checks that g1Powers (resp. g2Powers) are successive powers
+// of tau, where g1Tau = [tau]g1 and g2Tau = [tau]g2, using a single pairing check
+// on random linear combinations of the powers.
+func checkPowers(g1Powers []curve.G1Affine, g2Powers []curve.G2Affine, g1Tau *curve.G1Affine, g2Tau *curve.G2Affine, g1 *curve.G1Affine, g2 *curve.G2Affine) error {
+
+	// random coefficients, in regular form for the multi exponentiations
+	n := len(g1Powers) - 1
+	if len(g2Powers)-1 > n {
+		n = len(g2Powers) - 1
+	}
+	r := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		if _, err := r[i].SetRandom(); err != nil {
+			return err
+		}
+		r[i].FromMont()
+	}
+
+	// Sum_i r_i*[tau**i]G1 and Sum_i r_i*[tau**(i+1)]G1
+	var g1L, g1R curve.G1Affine
+	g1L.MultiExp(g1Powers[:len(g1Powers)-1], r[:len(g1Powers)-1])
+	g1R.MultiExp(g1Powers[1:], r[:len(g1Powers)-1])
+
+	// Sum_i r_i*[tau**i]G2 and Sum_i r_i*[tau**(i+1)]G2
+	var g2L, g2R curve.G2Affine
+	g2L.MultiExp(g2Powers[:len(g2Powers)-1], r[:len(g2Powers)-1])
+	g2R.MultiExp(g2Powers[1:], r[:len(g2Powers)-1])
+
+	// rho separates the two equations
+	var rho fr.Element
+	if _, err := rho.SetRandom(); err != nil {
+		return err
+	}
+	var bRho big.Int
+	rho.ToBigIntRegular(&bRho)
+	var rhoG1Tau, negRhoG1 curve.G1Affine
+	rhoG1Tau.ScalarMultiplication(g1Tau, &bRho)
+	negRhoG1.ScalarMultiplication(g1, &bRho).Neg(&negRhoG1)
+	g1R.Neg(&g1R)
+
+	check, err := curve.PairingCheck(
+		[]curve.G1Affine{g1L, g1R, rhoG1Tau, negRhoG1},
+		[]curve.G2Affine{*g2Tau, *g2, g2L, g2R},
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrInconsistentPowers
+	}
+	return nil
+}
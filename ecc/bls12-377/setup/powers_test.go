@@ -0,0 +1,88 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package setup
+
+import (
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// newTestPowers returns the first powers of tau, in G1 and G2
+func newTestPowers(nbG1, nbG2 int, tau uint64) PowersOfTau {
+	_, _, g1, g2 := curve.Generators()
+
+	var bTau fr.Element
+	bTau.SetUint64(tau)
+
+	n := nbG1
+	if nbG2 > n {
+		n = nbG2
+	}
+	scalars := make([]fr.Element, n)
+	scalars[0].SetOne()
+	for i := 1; i < n; i++ {
+		scalars[i].Mul(&scalars[i-1], &bTau)
+	}
+	for i := 0; i < n; i++ {
+		scalars[i].FromMont()
+	}
+
+	var res PowersOfTau
+	res.G1 = curve.BatchScalarMultiplicationG1(&g1, scalars[:nbG1])
+	res.G2 = curve.BatchScalarMultiplicationG2(&g2, scalars[:nbG2])
+	return res
+}
+
+func TestVerifyPowers(t *testing.T) {
+
+	powers := newTestPowers(31, 16, 42)
+	if err := powers.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	// tamper with a power in G1
+	var tmp curve.G1Affine
+	tmp = powers.G1[7]
+	powers.G1[7] = powers.G1[8]
+	if err := powers.Verify(); err != ErrInconsistentPowers {
+		t.Fatal("verifying inconsistent powers in G1 should have failed")
+	}
+	powers.G1[7] = tmp
+
+	// tamper with a power in G2
+	powers.G2[3].Neg(&powers.G2[3])
+	if err := powers.Verify(); err != ErrInconsistentPowers {
+		t.Fatal("verifying inconsistent powers in G2 should have failed")
+	}
+	powers.G2[3].Neg(&powers.G2[3])
+
+	// powers of tau with a different tau
+	other := newTestPowers(31, 16, 43)
+	powers.G2 = other.G2
+	if err := powers.Verify(); err != ErrInconsistentPowers {
+		t.Fatal("verifying powers of different taus should have failed")
+	}
+
+	// the first power must be the generator
+	powers = newTestPowers(31, 16, 42)
+	powers.G1 = powers.G1[1:]
+	if err := powers.Verify(); err != ErrInvalidGenerator {
+		t.Fatal("verifying powers not starting with the generator should have failed")
+	}
+}
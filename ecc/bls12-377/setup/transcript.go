@@ -0,0 +1,274 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package setup
+
+import (
+	"errors"
+	"math/big"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidContribution = errors.New("invalid contribution: proof of knowledge does not verify")
+	ErrInvalidTranscript   = errors.New("the powers of tau do not match the last contribution")
+)
+
+// dstContribution domain separation tag used to derive the challenges of the proofs of knowledge
+var dstContribution = []byte("GNARK_CRYPTO_POWERS_OF_TAU_BLS12377_G2_SVDW")
+
+// Transcript of a powers of tau ceremony: the current powers of tau and
+// the list of contributions that lead to them
+type Transcript struct {
+	Powers        PowersOfTau
+	Contributions []Contribution
+}
+
+// Contribution public part of a contribution to a powers of tau ceremony,
+// where the participant multiplied tau by a secret s
+type Contribution struct {
+	TauG1 curve.G1Affine // [tau]G1 after the contribution
+	Proof ProofOfKnowledge
+}
+
+// ProofOfKnowledge proof of knowledge of the secret s of a contribution
+type ProofOfKnowledge struct {
+	G1S  curve.G1Affine // [x]G1 for a random x
+	G1SX curve.G1Affine // [s*x]G1
+	G2SR curve.G2Affine // [s]R, with R the hash to G2 of the contribution
+}
+
+// NewTranscript returns the initial transcript of a ceremony producing nbG1 powers of tau
+// in G1 and nbG2 powers of tau in G2: all the powers are equal to the generators (tau = 1).
+func NewTranscript(nbG1, nbG2 int) (*Transcript, error) {
+	if nbG1 < 2 || nbG2 < 2 {
+		return nil, ErrNotEnoughPowers
+	}
+	_, _, g1, g2 := curve.Generators()
+
+	var t Transcript
+	t.Powers.G1 = make([]curve.G1Affine, nbG1)
+	t.Powers.G2 = make([]curve.G2Affine, nbG2)
+	for i := 0; i < nbG1; i++ {
+		t.Powers.G1[i] = g1
+	}
+	for i := 0; i < nbG2; i++ {
+		t.Powers.G2[i] = g2
+	}
+	return &t, nil
+}
+
+// Contribute rescales the powers of tau with a random secret s, that is tau becomes s*tau,
+// and appends the corresponding Contribution to the transcript. The secret is not kept.
+func (t *Transcript) Contribute() error {
+	var s, x fr.Element
+	if _, err := s.SetRandom(); err != nil {
+		return err
+	}
+	if _, err := x.SetRandom(); err != nil {
+		return err
+	}
+
+	// 1, s, s**2, ...
+	n := len(t.Powers.G1)
+	if len(t.Powers.G2) > n {
+		n = len(t.Powers.G2)
+	}
+	scalars := make([]fr.Element, n)
+	scalars[0].SetOne()
+	for i := 1; i < n; i++ {
+		scalars[i].Mul(&scalars[i-1], &s)
+	}
+	for i := 0; i < n; i++ {
+		scalars[i].FromMont()
+	}
+
+	// proof of knowledge of s
+	prevTauG1 := t.Powers.G1[1]
+	var c Contribution
+	var bs, bx, bsx big.Int
+	s.ToBigIntRegular(&bs)
+	x.ToBigIntRegular(&bx)
+	var sx fr.Element
+	sx.Mul(&s, &x).ToBigIntRegular(&bsx)
+
+	_, _, g1, _ := curve.Generators()
+	c.Proof.G1S.ScalarMultiplication(&g1, &bx)
+	c.Proof.G1SX.ScalarMultiplication(&g1, &bsx)
+	c.TauG1.ScalarMultiplication(&prevTauG1, &bs)
+	r, err := contributionChallenge(&prevTauG1, &c)
+	if err != nil {
+		return err
+	}
+	c.Proof.G2SR.ScalarMultiplication(&r, &bs)
+
+	// rescale the powers
+	if t.isInitial() {
+		// all the powers are the generators, the fixed base batch multiplication can be used
+		_, _, g1, g2 := curve.Generators()
+		t.Powers.G1 = curve.BatchScalarMultiplicationG1(&g1, scalars[:len(t.Powers.G1)])
+		t.Powers.G2 = curve.BatchScalarMultiplicationG2(&g2, scalars[:len(t.Powers.G2)])
+	} else {
+		scalePowersG1(t.Powers.G1, scalars)
+		scalePowersG2(t.Powers.G2, scalars)
+	}
+
+	t.Contributions = append(t.Contributions, c)
+	return nil
+}
+
+// Verify checks the whole chain of contributions, and that the powers of tau
+// are consistent and result from the last contribution.
+//
+// The proofs of knowledge of all the contributions are checked with a single pairing check,
+// the powers with another one (see PowersOfTau.Verify).
+func (t *Transcript) Verify() error {
+	if err := t.Powers.Verify(); err != nil {
+		return err
+	}
+
+	_, _, g1, _ := curve.Generators()
+	if len(t.Contributions) == 0 {
+		if !t.Powers.G1[1].Equal(&g1) {
+			return ErrInvalidTranscript
+		}
+		return nil
+	}
+	if !t.Powers.G1[1].Equal(&t.Contributions[len(t.Contributions)-1].TauG1) {
+		return ErrInvalidTranscript
+	}
+
+	// for each contribution, with prevTauG1 the [tau]G1 before the contribution, and R the challenge:
+	// e(G1S, G2SR) == e(G1SX, R) (knowledge of s)
+	// e(prevTauG1, G2SR) == e(TauG1, R) (tau is multiplied by s)
+	// with random coefficients a_i, b_i, all the equations are checked at once:
+	// Prod_i e(a_i*G1S - b_i*prevTauG1, G2SR) * e(b_i*TauG1 - a_i*G1SX, R) == 1
+	nbPairs := 2 * len(t.Contributions)
+	p := make([]curve.G1Affine, 0, nbPairs)
+	q := make([]curve.G2Affine, 0, nbPairs)
+
+	prevTauG1 := g1
+	for i := 0; i < len(t.Contributions); i++ {
+		c := &t.Contributions[i]
+		if c.TauG1.IsInfinity() || c.Proof.G1S.IsInfinity() || c.Proof.G2SR.IsInfinity() {
+			return ErrInvalidContribution
+		}
+		if !c.TauG1.IsInSubGroup() || !c.Proof.G1S.IsInSubGroup() || !c.Proof.G1SX.IsInSubGroup() || !c.Proof.G2SR.IsInSubGroup() {
+			return ErrInvalidPoint
+		}
+		r, err := contributionChallenge(&prevTauG1, c)
+		if err != nil {
+			return err
+		}
+
+		var a, b fr.Element
+		if _, err := a.SetRandom(); err != nil {
+			return err
+		}
+		if _, err := b.SetRandom(); err != nil {
+			return err
+		}
+		var ba, bb big.Int
+		a.ToBigIntRegular(&ba)
+		b.ToBigIntRegular(&bb)
+
+		var left, right, tmp curve.G1Jac
+		left.ScalarMultiplication(tmp.FromAffine(&c.Proof.G1S), &ba)
+		tmp.FromAffine(&prevTauG1)
+		tmp.ScalarMultiplication(&tmp, &bb)
+		left.SubAssign(&tmp)
+
+		right.ScalarMultiplication(tmp.FromAffine(&c.TauG1), &bb)
+		tmp.FromAffine(&c.Proof.G1SX)
+		tmp.ScalarMultiplication(&tmp, &ba)
+		right.SubAssign(&tmp)
+
+		var leftAff, rightAff curve.G1Affine
+		leftAff.FromJacobian(&left)
+		rightAff.FromJacobian(&right)
+		p = append(p, leftAff, rightAff)
+		q = append(q, c.Proof.G2SR, r)
+
+		prevTauG1 = c.TauG1
+	}
+
+	check, err := curve.PairingCheck(p, q)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrInvalidContribution
+	}
+	return nil
+}
+
+// isInitial returns true if the powers of tau are all equal to the generators
+func (t *Transcript) isInitial() bool {
+	_, _, g1, g2 := curve.Generators()
+	for i := 0; i < len(t.Powers.G1); i++ {
+		if !t.Powers.G1[i].Equal(&g1) {
+			return false
+		}
+	}
+	for i := 0; i < len(t.Powers.G2); i++ {
+		if !t.Powers.G2[i].Equal(&g2) {
+			return false
+		}
+	}
+	return true
+}
+
+// contributionChallenge returns the challenge R in G2 of the proof of knowledge of a contribution,
+// that is the hash to G2 of the [tau]G1 before and after the contribution, G1S and G1SX.
+func contributionChallenge(prevTauG1 *curve.G1Affine, c *Contribution) (curve.G2Affine, error) {
+	msg := make([]byte, 0, 4*curve.SizeOfG1AffineUncompressed)
+	for _, p := range []*curve.G1Affine{prevTauG1, &c.TauG1, &c.Proof.G1S, &c.Proof.G1SX} {
+		b := p.RawBytes()
+		msg = append(msg, b[:]...)
+	}
+	return curve.HashToCurveG2Svdw(msg, dstContribution)
+}
+
+// scalePowersG1 sets powers[i] to [scalars[i]]powers[i], scalars being in regular form
+func scalePowersG1(powers []curve.G1Affine, scalars []fr.Element) {
+	parallel.Execute(len(powers), func(start, end int) {
+		var b big.Int
+		var jac curve.G1Jac
+		for i := start; i < end; i++ {
+			scalars[i].ToBigInt(&b)
+			jac.FromAffine(&powers[i])
+			jac.ScalarMultiplication(&jac, &b)
+			powers[i].FromJacobian(&jac)
+		}
+	})
+}
+
+// scalePowersG2 sets powers[i] to [scalars[i]]powers[i], scalars being in regular form
+func scalePowersG2(powers []curve.G2Affine, scalars []fr.Element) {
+	parallel.Execute(len(powers), func(start, end int) {
+		var b big.Int
+		var jac curve.G2Jac
+		for i := start; i < end; i++ {
+			scalars[i].ToBigInt(&b)
+			jac.FromAffine(&powers[i])
+			jac.ScalarMultiplication(&jac, &b)
+			powers[i].FromJacobian(&jac)
+		}
+	})
+}
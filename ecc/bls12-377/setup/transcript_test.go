@@ -0,0 +1,135 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package setup
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	curve "github.com/consensys/gnark-crypto/ecc/bls12-377"
+)
+
+func TestTranscript(t *testing.T) {
+
+	transcript, err := NewTranscript(17, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := transcript.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := transcript.Contribute(); err != nil {
+			t.Fatal(err)
+		}
+		if err := transcript.Verify(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the powers are no longer the generators
+	_, _, g1, g2 := curve.Generators()
+	if transcript.Powers.G1[1].Equal(&g1) || transcript.Powers.G2[1].Equal(&g2) {
+		t.Fatal("contributions should have changed tau")
+	}
+
+	// serialization
+	var buf bytes.Buffer
+	written, err := transcript.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reconstructed Transcript
+	read, err := reconstructed.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != written {
+		t.Fatal("didn't read as many bytes as written")
+	}
+	if !reflect.DeepEqual(transcript, &reconstructed) {
+		t.Fatal("reconstructed transcript doesn't match original")
+	}
+}
+
+func TestTranscriptInvalid(t *testing.T) {
+
+	transcript, err := NewTranscript(9, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := transcript.Contribute(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// proof of knowledge of another contribution
+	c := transcript.Contributions[1]
+	transcript.Contributions[1].Proof = transcript.Contributions[0].Proof
+	if err := transcript.Verify(); err != ErrInvalidContribution {
+		t.Fatal("verifying an invalid proof of knowledge should have failed")
+	}
+	transcript.Contributions[1] = c
+
+	// a contribution is removed from the chain
+	first := transcript.Contributions[0]
+	transcript.Contributions = transcript.Contributions[1:]
+	if err := transcript.Verify(); err != ErrInvalidContribution {
+		t.Fatal("verifying a broken chain of contributions should have failed")
+	}
+	transcript.Contributions = append([]Contribution{first}, transcript.Contributions...)
+
+	// powers that don't result from the last contribution
+	other, err := NewTranscript(9, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Contribute(); err != nil {
+		t.Fatal(err)
+	}
+	powers := transcript.Powers
+	transcript.Powers = other.Powers
+	if err := transcript.Verify(); err != ErrInvalidTranscript {
+		t.Fatal("verifying powers not matching the contributions should have failed")
+	}
+	transcript.Powers = powers
+
+	if err := transcript.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkContribute(b *testing.B) {
+	const nbG1, nbG2 = 1 << 10, 1 << 5
+	transcript, err := NewTranscript(nbG1, nbG2)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := transcript.Contribute(); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := transcript.Contribute(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
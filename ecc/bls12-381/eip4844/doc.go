@@ -0,0 +1,22 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eip4844 implements the KZG commitments to blobs of EIP-4844, as specified in
+// the polynomial commitments of the Ethereum consensus specs (Deneb).
+//
+// Blobs are polynomials of size 4096 over bls12-381's fr, in evaluation form over the roots of
+// unity in bit reversed order. They are committed to with the Lagrange basis of the trusted setup.
+//
+// Specification: https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md
+package eip4844
@@ -0,0 +1,510 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	bls12381_pol "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+const (
+	BytesPerFieldElement = fr.Bytes
+	FieldElementsPerBlob = 4096
+	BytesPerBlob         = BytesPerFieldElement * FieldElementsPerBlob
+	BytesPerCommitment   = bls12381.SizeOfG1AffineCompressed
+	BytesPerProof        = bls12381.SizeOfG1AffineCompressed
+)
+
+// domain separation tags of the Fiat Shamir challenges
+const (
+	fiatShamirProtocolDomain      = "FSBLOBVERIFY_V1_"
+	randomChallengeKZGBatchDomain = "RCKZGBATCH___V1_"
+)
+
+var (
+	ErrNonCanonicalFieldElement = errors.New("the field element is not smaller than the modulus")
+	ErrInvalidPoint             = errors.New("invalid compressed G1 point")
+	ErrInvalidNbInputs          = errors.New("the numbers of blobs, commitments and proofs must be the same")
+	ErrVerifyOpeningProof       = errors.New("can't verify opening proof")
+	ErrVerifyBatchOpeningProofs = errors.New("can't verify batch of opening proofs")
+)
+
+// Blob polynomial of size FieldElementsPerBlob, in evaluation form over the roots of unity
+// in bit reversed order, each evaluation being encoded on 32 bytes in big endian.
+type Blob [BytesPerBlob]byte
+
+// Bytes32 big endian encoding of a field element
+type Bytes32 [BytesPerFieldElement]byte
+
+// KZGCommitment compressed commitment to a blob
+type KZGCommitment [BytesPerCommitment]byte
+
+// KZGProof compressed opening proof
+type KZGProof [BytesPerProof]byte
+
+// Context stores the trusted setup and the precomputed data used by the EIP-4844 functions.
+// It can be used concurrently.
+type Context struct {
+
+	// rootsOfUnity roots of unity of order FieldElementsPerBlob, in bit reversed order
+	rootsOfUnity []fr.Element
+
+	// g1Lagrange Lagrange basis of the setup, in bit reversed order
+	g1Lagrange []bls12381.G1Affine
+
+	// g1, g2 generators
+	g1 bls12381.G1Affine
+	g2 bls12381.G2Affine
+
+	// g2Tau [tau]g2
+	g2Tau bls12381.G2Affine
+}
+
+// NewContext returns a new context built on the trusted setup
+func NewContext(setup *TrustedSetup) (*Context, error) {
+	if err := setup.check(); err != nil {
+		return nil, err
+	}
+
+	var ctx Context
+	_, _, ctx.g1, ctx.g2 = bls12381.Generators()
+	ctx.g2Tau = setup.G2Monomial[1]
+
+	domain := fft.NewDomain(FieldElementsPerBlob, 0, false)
+	ctx.rootsOfUnity = make([]fr.Element, FieldElementsPerBlob)
+	ctx.rootsOfUnity[0].SetOne()
+	for i := 1; i < FieldElementsPerBlob; i++ {
+		ctx.rootsOfUnity[i].Mul(&ctx.rootsOfUnity[i-1], &domain.Generator)
+	}
+	fft.BitReverse(ctx.rootsOfUnity)
+
+	ctx.g1Lagrange = make([]bls12381.G1Affine, FieldElementsPerBlob)
+	copy(ctx.g1Lagrange, setup.G1Lagrange)
+	bitReverse(ctx.g1Lagrange)
+
+	return &ctx, nil
+}
+
+// BlobToKZGCommitment returns the commitment to the blob
+func (ctx *Context) BlobToKZGCommitment(blob *Blob) (KZGCommitment, error) {
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	return KZGCommitment(ctx.g1Lincomb(ctx.g1Lagrange, polynomial).Bytes()), nil
+}
+
+// ComputeKZGProof returns the opening proof of the blob at z, along with the evaluation y
+func (ctx *Context) ComputeKZGProof(blob *Blob, z Bytes32) (KZGProof, Bytes32, error) {
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return KZGProof{}, Bytes32{}, err
+	}
+	_z, err := bytesToBLSField(z)
+	if err != nil {
+		return KZGProof{}, Bytes32{}, err
+	}
+	proof, y := ctx.computeKZGProof(polynomial, &_z)
+	return proof, Bytes32(y.Bytes()), nil
+}
+
+// ComputeBlobKZGProof returns the opening proof of the blob at the Fiat Shamir challenge
+// derived from the blob and its commitment. The commitment is not checked against the blob.
+func (ctx *Context) ComputeBlobKZGProof(blob *Blob, commitment KZGCommitment) (KZGProof, error) {
+	if _, err := bytesToKZGPoint(commitment); err != nil {
+		return KZGProof{}, err
+	}
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return KZGProof{}, err
+	}
+	z := computeChallenge(blob, commitment)
+	proof, _ := ctx.computeKZGProof(polynomial, &z)
+	return proof, nil
+}
+
+// VerifyKZGProof verifies that proof is an opening proof of the polynomial committed to
+// by commitment, evaluating to y at z.
+func (ctx *Context) VerifyKZGProof(commitment KZGCommitment, z, y Bytes32, proof KZGProof) error {
+	_commitment, err := bytesToKZGPoint(commitment)
+	if err != nil {
+		return err
+	}
+	_z, err := bytesToBLSField(z)
+	if err != nil {
+		return err
+	}
+	_y, err := bytesToBLSField(y)
+	if err != nil {
+		return err
+	}
+	_proof, err := bytesToKZGPoint(proof)
+	if err != nil {
+		return err
+	}
+	return ctx.verifyKZGProof(&_commitment, &_z, &_y, &_proof)
+}
+
+// VerifyBlobKZGProof verifies the opening proof computed by ComputeBlobKZGProof
+func (ctx *Context) VerifyBlobKZGProof(blob *Blob, commitment KZGCommitment, proof KZGProof) error {
+	_commitment, err := bytesToKZGPoint(commitment)
+	if err != nil {
+		return err
+	}
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return err
+	}
+	z := computeChallenge(blob, commitment)
+	y := ctx.evaluate(polynomial, &z)
+	_proof, err := bytesToKZGPoint(proof)
+	if err != nil {
+		return err
+	}
+	return ctx.verifyKZGProof(&_commitment, &z, &y, &_proof)
+}
+
+// VerifyBlobKZGProofBatch verifies a batch of opening proofs computed by ComputeBlobKZGProof,
+// with a single pairing check.
+func (ctx *Context) VerifyBlobKZGProofBatch(blobs []Blob, commitments []KZGCommitment, proofs []KZGProof) error {
+	if len(blobs) != len(commitments) || len(blobs) != len(proofs) {
+		return ErrInvalidNbInputs
+	}
+
+	n := len(blobs)
+	_commitments := make([]bls12381.G1Affine, n)
+	_proofs := make([]bls12381.G1Affine, n)
+	zs := make([]fr.Element, n)
+	ys := make([]fr.Element, n)
+
+	var chErr = make(chan error, 1)
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			var err error
+			_commitments[i], err = bytesToKZGPoint(commitments[i])
+			if err == nil {
+				var polynomial []fr.Element
+				if polynomial, err = blobToPolynomial(&blobs[i]); err == nil {
+					zs[i] = computeChallenge(&blobs[i], commitments[i])
+					ys[i] = ctx.evaluate(polynomial, &zs[i])
+					_proofs[i], err = bytesToKZGPoint(proofs[i])
+				}
+			}
+			if err != nil {
+				select {
+				case chErr <- err:
+				default:
+				}
+				return
+			}
+		}
+	})
+	select {
+	case err := <-chErr:
+		return err
+	default:
+	}
+
+	return ctx.verifyKZGProofBatch(commitments, _commitments, zs, ys, proofs, _proofs)
+}
+
+// computeKZGProof returns the opening proof of the polynomial at z, along with the evaluation
+func (ctx *Context) computeKZGProof(polynomial []fr.Element, z *fr.Element) (KZGProof, fr.Element) {
+	y := ctx.evaluate(polynomial, z)
+
+	// quotient (p(X) - y)/(X - z) in evaluation form. If z is a root of unity, the quotient
+	// at z is computed from the other evaluations
+	denominators := make([]fr.Element, FieldElementsPerBlob)
+	inDomain := -1
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		denominators[i].Sub(&ctx.rootsOfUnity[i], z)
+		if denominators[i].IsZero() {
+			inDomain = i
+			denominators[i].SetOne()
+		}
+	}
+	denominators = bls12381_pol.BatchInvert(denominators)
+
+	quotient := make([]fr.Element, FieldElementsPerBlob)
+	parallel.Execute(FieldElementsPerBlob, func(start, end int) {
+		for i := start; i < end; i++ {
+			quotient[i].Sub(&polynomial[i], &y).Mul(&quotient[i], &denominators[i])
+		}
+	})
+	if inDomain != -1 {
+		quotient[inDomain] = ctx.quotientWithinDomain(polynomial, z, &y)
+	}
+
+	return KZGProof(ctx.g1Lincomb(ctx.g1Lagrange, quotient).Bytes()), y
+}
+
+// quotientWithinDomain returns the value at z of the quotient (p(X) - y)/(X - z), z being a root of unity:
+// Sum_{w_i != z} (p(w_i) - y)*w_i/(z*(z - w_i))
+func (ctx *Context) quotientWithinDomain(polynomial []fr.Element, z, y *fr.Element) fr.Element {
+	denominators := make([]fr.Element, 0, FieldElementsPerBlob-1)
+	numerators := make([]fr.Element, 0, FieldElementsPerBlob-1)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		if ctx.rootsOfUnity[i].Equal(z) {
+			continue
+		}
+		var num, den fr.Element
+		num.Sub(&polynomial[i], y).Mul(&num, &ctx.rootsOfUnity[i])
+		den.Sub(z, &ctx.rootsOfUnity[i]).Mul(&den, z)
+		numerators = append(numerators, num)
+		denominators = append(denominators, den)
+	}
+	denominators = bls12381_pol.BatchInvert(denominators)
+
+	var res, t fr.Element
+	for i := 0; i < len(numerators); i++ {
+		t.Mul(&numerators[i], &denominators[i])
+		res.Add(&res, &t)
+	}
+	return res
+}
+
+// evaluate returns the evaluation at z of the polynomial in evaluation form, with the
+// barycentric formula: (z**n - 1)/n * Sum_i p(w_i)*w_i/(z - w_i)
+func (ctx *Context) evaluate(polynomial []fr.Element, z *fr.Element) fr.Element {
+	denominators := make([]fr.Element, FieldElementsPerBlob)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		if ctx.rootsOfUnity[i].Equal(z) {
+			return polynomial[i]
+		}
+		denominators[i].Sub(z, &ctx.rootsOfUnity[i])
+	}
+	denominators = bls12381_pol.BatchInvert(denominators)
+
+	var res, t fr.Element
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		t.Mul(&polynomial[i], &ctx.rootsOfUnity[i]).Mul(&t, &denominators[i])
+		res.Add(&res, &t)
+	}
+
+	var zn, nInv fr.Element
+	zn.Exp(*z, big.NewInt(FieldElementsPerBlob))
+	zn.Sub(&zn, &ctx.rootsOfUnity[0]) // rootsOfUnity[0] == 1
+	nInv.SetUint64(FieldElementsPerBlob).Inverse(&nInv)
+	res.Mul(&res, &zn).Mul(&res, &nInv)
+
+	return res
+}
+
+// verifyKZGProof checks e(commitment - [y]g1, g2) == e(proof, [tau]g2 - [z]g2)
+func (ctx *Context) verifyKZGProof(commitment *bls12381.G1Affine, z, y *fr.Element, proof *bls12381.G1Affine) error {
+	var bi big.Int
+
+	// [tau - z]g2
+	var xMinusZ, gz bls12381.G2Jac
+	xMinusZ.FromAffine(&ctx.g2Tau)
+	gz.FromAffine(&ctx.g2)
+	gz.ScalarMultiplication(&gz, z.ToBigIntRegular(&bi))
+	xMinusZ.SubAssign(&gz)
+	var xMinusZAff bls12381.G2Affine
+	xMinusZAff.FromJacobian(&xMinusZ)
+
+	// commitment - [y]g1
+	var pMinusY, gy bls12381.G1Jac
+	pMinusY.FromAffine(commitment)
+	gy.FromAffine(&ctx.g1)
+	gy.ScalarMultiplication(&gy, y.ToBigIntRegular(&bi))
+	pMinusY.SubAssign(&gy)
+	var pMinusYAff bls12381.G1Affine
+	pMinusYAff.FromJacobian(&pMinusY)
+
+	var minusG2 bls12381.G2Affine
+	minusG2.Neg(&ctx.g2)
+
+	ok, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{pMinusYAff, *proof},
+		[]bls12381.G2Affine{minusG2, xMinusZAff},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// verifyKZGProofBatch verifies the opening proofs with a random linear combination,
+// the randomness r being derived from all the inputs:
+// e(Sum_i r**i*proof_i, [tau]g2) == e(Sum_i r**i*(commitment_i - [y_i]g1 + z_i*proof_i), g2)
+func (ctx *Context) verifyKZGProofBatch(commitmentsBytes []KZGCommitment, commitments []bls12381.G1Affine, zs, ys []fr.Element, proofsBytes []KZGProof, proofs []bls12381.G1Affine) error {
+	n := len(commitments)
+	if n == 0 {
+		return nil
+	}
+
+	// derive r from the inputs
+	var data bytes.Buffer
+	var size [8]byte
+	data.WriteString(randomChallengeKZGBatchDomain)
+	binary.BigEndian.PutUint64(size[:], FieldElementsPerBlob)
+	data.Write(size[:])
+	binary.BigEndian.PutUint64(size[:], uint64(n))
+	data.Write(size[:])
+	for i := 0; i < n; i++ {
+		z, y := zs[i].Bytes(), ys[i].Bytes()
+		data.Write(commitmentsBytes[i][:])
+		data.Write(z[:])
+		data.Write(y[:])
+		data.Write(proofsBytes[i][:])
+	}
+	r := hashToBLSField(data.Bytes())
+
+	rPowers := make([]fr.Element, n)
+	rPowers[0].SetOne()
+	for i := 1; i < n; i++ {
+		rPowers[i].Mul(&rPowers[i-1], &r)
+	}
+
+	// Sum_i r**i*proof_i
+	proofLincomb := ctx.g1Lincomb(proofs, rPowers)
+
+	// Sum_i r**i*(commitment_i - [y_i]g1 + z_i*proof_i)
+	// = Sum_i r**i*commitment_i + (-Sum_i r**i*y_i)*g1 + Sum_i r**i*z_i*proof_i
+	points := make([]bls12381.G1Affine, 0, 2*n+1)
+	scalars := make([]fr.Element, 0, 2*n+1)
+	var sumY, t fr.Element
+	for i := 0; i < n; i++ {
+		points = append(points, commitments[i], proofs[i])
+		t.Mul(&rPowers[i], &zs[i])
+		scalars = append(scalars, rPowers[i], t)
+		t.Mul(&rPowers[i], &ys[i])
+		sumY.Add(&sumY, &t)
+	}
+	sumY.Neg(&sumY)
+	points = append(points, ctx.g1)
+	scalars = append(scalars, sumY)
+	rhs := ctx.g1Lincomb(points, scalars)
+
+	var minusG2Tau bls12381.G2Affine
+	minusG2Tau.Neg(&ctx.g2Tau)
+
+	ok, err := bls12381.PairingCheck(
+		[]bls12381.G1Affine{*proofLincomb, *rhs},
+		[]bls12381.G2Affine{minusG2Tau, ctx.g2},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrVerifyBatchOpeningProofs
+	}
+	return nil
+}
+
+// g1Lincomb returns Sum_i scalars[i]*points[i]
+func (ctx *Context) g1Lincomb(points []bls12381.G1Affine, scalars []fr.Element) *bls12381.G1Affine {
+	regular := make([]fr.Element, len(scalars))
+	for i := 0; i < len(scalars); i++ {
+		regular[i] = scalars[i]
+		regular[i].FromMont()
+	}
+	var res bls12381.G1Affine
+	res.MultiExp(points[:len(scalars)], regular)
+	return &res
+}
+
+// computeChallenge returns the Fiat Shamir challenge at which a blob is opened:
+// the hash of the domain, the degree on 16 bytes, the blob and its commitment
+func computeChallenge(blob *Blob, commitment KZGCommitment) fr.Element {
+	var degree [16]byte
+	binary.BigEndian.PutUint64(degree[8:], FieldElementsPerBlob)
+
+	var data bytes.Buffer
+	data.Grow(len(fiatShamirProtocolDomain) + len(degree) + BytesPerBlob + BytesPerCommitment)
+	data.WriteString(fiatShamirProtocolDomain)
+	data.Write(degree[:])
+	data.Write(blob[:])
+	data.Write(commitment[:])
+
+	return hashToBLSField(data.Bytes())
+}
+
+// hashToBLSField returns the sha256 hash of data, reduced modulo r
+func hashToBLSField(data []byte) fr.Element {
+	h := sha256.Sum256(data)
+	var res fr.Element
+	res.SetBytes(h[:])
+	return res
+}
+
+// bytesToBLSField decodes a field element, which must be canonical
+func bytesToBLSField(b Bytes32) (fr.Element, error) {
+	var bi big.Int
+	bi.SetBytes(b[:])
+	if bi.Cmp(fr.Modulus()) != -1 {
+		return fr.Element{}, ErrNonCanonicalFieldElement
+	}
+	var res fr.Element
+	res.SetBigInt(&bi)
+	return res, nil
+}
+
+// blobToPolynomial decodes the evaluations of the blob
+func blobToPolynomial(blob *Blob) ([]fr.Element, error) {
+	res := make([]fr.Element, FieldElementsPerBlob)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		var b Bytes32
+		copy(b[:], blob[i*BytesPerFieldElement:(i+1)*BytesPerFieldElement])
+		var err error
+		if res[i], err = bytesToBLSField(b); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// bytesToKZGPoint decodes a compressed commitment or proof. The point at infinity is accepted,
+// other points must be in G1, and the encoding must be canonical.
+func bytesToKZGPoint(b [bls12381.SizeOfG1AffineCompressed]byte) (bls12381.G1Affine, error) {
+	var res bls12381.G1Affine
+	if _, err := res.SetBytes(b[:]); err != nil {
+		return bls12381.G1Affine{}, ErrInvalidPoint
+	}
+	// SetBytes doesn't check that the coordinate is reduced, nor the bits left
+	// unused by the point at infinity
+	if res.Bytes() != b {
+		return bls12381.G1Affine{}, ErrInvalidPoint
+	}
+	return res, nil
+}
+
+// bitReverse applies the bit-reversal permutation to a.
+// len(a) must be a power of 2
+func bitReverse(a []bls12381.G1Affine) {
+	n := uint64(len(a))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
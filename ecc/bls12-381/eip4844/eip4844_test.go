@@ -0,0 +1,370 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"bytes"
+	"math/big"
+	"sync"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	bls12381_pol "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/polynomial"
+)
+
+// testTau secret of the insecure setup used in the tests
+var testTau = new(big.Int).SetUint64(42424242)
+
+var (
+	testSetup     *TrustedSetup
+	testCtx       *Context
+	testSetupOnce sync.Once
+)
+
+// newTestSetup returns an insecure setup with the secret testTau:
+// L_i(tau) = (w**i/n)*(tau**n - 1)/(tau - w**i)
+func newTestSetup() *TrustedSetup {
+	var tau, tauN, nInv fr.Element
+	one := fr.One()
+	tau.SetBigInt(testTau)
+	tauN.Exp(tau, big.NewInt(FieldElementsPerBlob))
+	tauN.Sub(&tauN, &one)
+	nInv.SetUint64(FieldElementsPerBlob).Inverse(&nInv)
+
+	domain := fft.NewDomain(FieldElementsPerBlob, 0, false)
+	lagrange := make([]fr.Element, FieldElementsPerBlob)
+	denominators := make([]fr.Element, FieldElementsPerBlob)
+	var w fr.Element
+	w.SetOne()
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		lagrange[i].Mul(&w, &nInv).Mul(&lagrange[i], &tauN)
+		denominators[i].Sub(&tau, &w)
+		w.Mul(&w, &domain.Generator)
+	}
+	denominators = bls12381_pol.BatchInvert(denominators)
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		lagrange[i].Mul(&lagrange[i], &denominators[i])
+		lagrange[i].FromMont()
+	}
+
+	var setup TrustedSetup
+	_, _, g1, g2 := bls12381.Generators()
+	setup.G1Lagrange = bls12381.BatchScalarMultiplicationG1(&g1, lagrange)
+	setup.G2Monomial = make([]bls12381.G2Affine, 2)
+	setup.G2Monomial[0] = g2
+	setup.G2Monomial[1].ScalarMultiplication(&g2, testTau)
+
+	return &setup
+}
+
+func getTestContext(t testing.TB) *Context {
+	testSetupOnce.Do(func() {
+		testSetup = newTestSetup()
+		var err error
+		if testCtx, err = NewContext(testSetup); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return testCtx
+}
+
+func randomBlob() *Blob {
+	var blob Blob
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		var e fr.Element
+		e.SetRandom()
+		b := e.Bytes()
+		copy(blob[i*BytesPerFieldElement:], b[:])
+	}
+	return &blob
+}
+
+func randomBytes32() Bytes32 {
+	var e fr.Element
+	e.SetRandom()
+	return Bytes32(e.Bytes())
+}
+
+func TestTrustedSetupJSON(t *testing.T) {
+	getTestContext(t)
+
+	var buf bytes.Buffer
+	if err := testSetup.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	setup, err := ReadTrustedSetupJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		if !setup.G1Lagrange[i].Equal(&testSetup.G1Lagrange[i]) {
+			t.Fatal("json serialization failed")
+		}
+	}
+	if !setup.G2Monomial[1].Equal(&testSetup.G2Monomial[1]) {
+		t.Fatal("json serialization failed")
+	}
+
+	// previous keys of the setup file
+	legacy := bytes.Replace(buf.Bytes(), []byte("g1_lagrange"), []byte("setup_G1_lagrange"), 1)
+	legacy = bytes.Replace(legacy, []byte("g2_monomial"), []byte("setup_G2"), 1)
+	if _, err := ReadTrustedSetupJSON(bytes.NewReader(legacy)); err != nil {
+		t.Fatal(err)
+	}
+
+	// a point is changed
+	var tampered TrustedSetup
+	tampered.G1Lagrange = make([]bls12381.G1Affine, FieldElementsPerBlob)
+	copy(tampered.G1Lagrange, testSetup.G1Lagrange)
+	tampered.G1Lagrange[7] = tampered.G1Lagrange[8]
+	tampered.G2Monomial = testSetup.G2Monomial
+	buf.Reset()
+	if err := tampered.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadTrustedSetupJSON(&buf); err != ErrInvalidTrustedSetup {
+		t.Fatal("a setup whose Lagrange points don't sum to the generator should be rejected")
+	}
+	if _, err := NewContext(&tampered); err != ErrInvalidTrustedSetup {
+		t.Fatal("a setup whose Lagrange points don't sum to the generator should be rejected")
+	}
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	ctx := getTestContext(t)
+
+	// the blob is the evaluation of a random polynomial p, in bit reversed order
+	domain := fft.NewDomain(FieldElementsPerBlob, 0, false)
+	p := make([]fr.Element, FieldElementsPerBlob)
+	for i := 0; i < len(p); i++ {
+		p[i].SetRandom()
+	}
+	var tau, pTau fr.Element
+	tau.SetBigInt(testTau)
+	for i := len(p) - 1; i >= 0; i-- {
+		pTau.Mul(&pTau, &tau).Add(&pTau, &p[i])
+	}
+	domain.FFT(p, fft.DIF, 0)
+	var blob Blob
+	for i := 0; i < FieldElementsPerBlob; i++ {
+		b := p[i].Bytes()
+		copy(blob[i*BytesPerFieldElement:], b[:])
+	}
+
+	commitment, err := ctx.BlobToKZGCommitment(&blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// [p(tau)]g1
+	var expected bls12381.G1Affine
+	_, _, g1, _ := bls12381.Generators()
+	expected.ScalarMultiplication(&g1, pTau.ToBigIntRegular(new(big.Int)))
+	if commitment != KZGCommitment(expected.Bytes()) {
+		t.Fatal("wrong commitment")
+	}
+
+	// the zero blob commits to the point at infinity
+	commitment, err = ctx.BlobToKZGCommitment(&Blob{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var infinity bls12381.G1Affine
+	if commitment != KZGCommitment(infinity.Bytes()) {
+		t.Fatal("the zero blob should commit to the point at infinity")
+	}
+
+	// non canonical field element
+	blob[BytesPerFieldElement] = 0xff
+	if _, err := ctx.BlobToKZGCommitment(&blob); err != ErrNonCanonicalFieldElement {
+		t.Fatal("a blob with a non canonical field element should be rejected")
+	}
+}
+
+func TestKZGProof(t *testing.T) {
+	ctx := getTestContext(t)
+
+	blob := randomBlob()
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// random point, and points of the domain
+	w := Bytes32(ctx.rootsOfUnity[17].Bytes())
+	for _, z := range []Bytes32{randomBytes32(), w} {
+		proof, y, err := ctx.ComputeKZGProof(blob, z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.VerifyKZGProof(commitment, z, y, proof); err != nil {
+			t.Fatal(err)
+		}
+
+		// wrong value
+		_y := randomBytes32()
+		if err := ctx.VerifyKZGProof(commitment, z, _y, proof); err != ErrVerifyOpeningProof {
+			t.Fatal("verifying an opening to a wrong value should have failed")
+		}
+
+		// wrong point
+		if err := ctx.VerifyKZGProof(commitment, randomBytes32(), y, proof); err != ErrVerifyOpeningProof {
+			t.Fatal("verifying an opening at a wrong point should have failed")
+		}
+	}
+
+	// the evaluation at a point of the domain is the value stored in the blob
+	_, y, err := ctx.ComputeKZGProof(blob, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(y[:], blob[17*BytesPerFieldElement:18*BytesPerFieldElement]) {
+		t.Fatal("wrong evaluation at a point of the domain")
+	}
+
+	// invalid encodings
+	var tooLarge Bytes32
+	for i := range tooLarge {
+		tooLarge[i] = 0xff
+	}
+	if _, _, err := ctx.ComputeKZGProof(blob, tooLarge); err != ErrNonCanonicalFieldElement {
+		t.Fatal("a non canonical point should be rejected")
+	}
+	proof, y, _ := ctx.ComputeKZGProof(blob, w)
+	if err := ctx.VerifyKZGProof(commitment, w, tooLarge, proof); err != ErrNonCanonicalFieldElement {
+		t.Fatal("a non canonical value should be rejected")
+	}
+	proof[5] ^= 1
+	if err := ctx.VerifyKZGProof(commitment, w, y, proof); err != ErrInvalidPoint {
+		t.Fatal("an invalid proof encoding should be rejected")
+	}
+}
+
+func TestBlobKZGProof(t *testing.T) {
+	ctx := getTestContext(t)
+
+	const nbBlobs = 4
+	blobs := make([]Blob, nbBlobs)
+	commitments := make([]KZGCommitment, nbBlobs)
+	proofs := make([]KZGProof, nbBlobs)
+	for i := 0; i < nbBlobs; i++ {
+		blobs[i] = *randomBlob()
+		var err error
+		if commitments[i], err = ctx.BlobToKZGCommitment(&blobs[i]); err != nil {
+			t.Fatal(err)
+		}
+		if proofs[i], err = ctx.ComputeBlobKZGProof(&blobs[i], commitments[i]); err != nil {
+			t.Fatal(err)
+		}
+		if err := ctx.VerifyBlobKZGProof(&blobs[i], commitments[i], proofs[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs[1:]); err != ErrInvalidNbInputs {
+		t.Fatal("inputs of different sizes should be rejected")
+	}
+
+	// swapped proofs
+	if err := ctx.VerifyBlobKZGProof(&blobs[0], commitments[0], proofs[1]); err != ErrVerifyOpeningProof {
+		t.Fatal("verifying the proof of another blob should have failed")
+	}
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != ErrVerifyBatchOpeningProofs {
+		t.Fatal("verifying a batch with swapped proofs should have failed")
+	}
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+
+	// modified blob
+	blobs[2][100] ^= 1
+	if err := ctx.VerifyBlobKZGProof(&blobs[2], commitments[2], proofs[2]); err != ErrVerifyOpeningProof {
+		t.Fatal("verifying the proof of a modified blob should have failed")
+	}
+	if err := ctx.VerifyBlobKZGProofBatch(blobs, commitments, proofs); err != ErrVerifyBatchOpeningProofs {
+		t.Fatal("verifying a batch with a modified blob should have failed")
+	}
+}
+
+func TestBytesToKZGPoint(t *testing.T) {
+	_, _, g1, _ := bls12381.Generators()
+
+	// valid points
+	for _, p := range []bls12381.G1Affine{g1, {}} {
+		b := p.Bytes()
+		q, err := bytesToKZGPoint(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !q.Equal(&p) {
+			t.Fatal("wrong point")
+		}
+	}
+
+	// infinity with non zero bits
+	var infinity bls12381.G1Affine
+	b := infinity.Bytes()
+	b[47] = 1
+	if _, err := bytesToKZGPoint(b); err != ErrInvalidPoint {
+		t.Fatal("a non canonical encoding of the point at infinity should be rejected")
+	}
+
+	// uncompressed flag
+	b = g1.Bytes()
+	b[0] &= 0x7f
+	if _, err := bytesToKZGPoint(b); err != ErrInvalidPoint {
+		t.Fatal("an uncompressed encoding should be rejected")
+	}
+}
+
+func BenchmarkBlobToKZGCommitment(b *testing.B) {
+	ctx := getTestContext(b)
+	blob := randomBlob()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx.BlobToKZGCommitment(blob)
+	}
+}
+
+func BenchmarkComputeBlobKZGProof(b *testing.B) {
+	ctx := getTestContext(b)
+	blob := randomBlob()
+	commitment, _ := ctx.BlobToKZGCommitment(blob)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx.ComputeBlobKZGProof(blob, commitment)
+	}
+}
+
+func BenchmarkVerifyBlobKZGProof(b *testing.B) {
+	ctx := getTestContext(b)
+	blob := randomBlob()
+	commitment, _ := ctx.BlobToKZGCommitment(blob)
+	proof, _ := ctx.ComputeBlobKZGProof(blob, commitment)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx.VerifyBlobKZGProof(blob, commitment, proof)
+	}
+}